@@ -0,0 +1,143 @@
+package sbpfx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mistermoe/sbpfx/internal/fsutil"
+)
+
+const cacheDirPerm = 0o750
+
+// CacheEntry is a downloaded rate-sheet PDF and where and when it came from.
+type CacheEntry struct {
+	Date         time.Time `json:"date"`                    // Business date of the sheet
+	URL          string    `json:"url"`                     // Resolved URL that served the PDF
+	SHA256       string    `json:"sha256"`                  // Hex-encoded SHA-256 of Content
+	FetchedAt    time.Time `json:"fetched_at"`              // When Content was downloaded
+	CheckedAt    time.Time `json:"checked_at"`              // When SBP last confirmed Content is current
+	ChangedAt    time.Time `json:"changed_at"`              // When Content was first seen or last changed
+	ETag         string    `json:"etag,omitempty"`          // ETag validator SBP sent with Content
+	LastModified string    `json:"last_modified,omitempty"` // Last-Modified validator SBP sent with Content
+	Content      []byte    `json:"-"`                       // Raw PDF bytes
+}
+
+// Cache stores downloaded rate-sheet PDFs by business date so that repeated and
+// historical lookups don't go back to SBP. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the entry for a business date. It reports false, with a nil
+	// error, on a miss.
+	Get(ctx context.Context, date time.Time) (*CacheEntry, bool, error)
+	// Put stores an entry, replacing any existing entry for its date.
+	Put(ctx context.Context, entry *CacheEntry) error
+}
+
+// WithCache makes the client look up downloaded PDFs in cache before going to
+// SBP, and store the ones it downloads. A failure to store one is logged, not
+// returned. Earlier sheets are served straight from
+// the cache. Today's sheet can still be re-posted, so a cached copy of it is
+// revalidated with a conditional GET (If-None-Match / If-Modified-Since) and
+// only downloaded again if it changed; CacheEntry.ChangedAt records when it
+// last did.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// FSCache is a Cache on the local filesystem. PDFs are stored once per content
+// hash under objects/, and index/ maps each business date to its hash and
+// resolved URL:
+//
+//	<dir>/objects/<sha256>.pdf
+//	<dir>/index/<YYYY-MM-DD>.json
+//
+// Files are written atomically, so an FSCache directory can be shared by
+// several processes.
+type FSCache struct {
+	dir string
+}
+
+// NewFSCache returns an FSCache rooted at dir, creating it if needed.
+func NewFSCache(dir string) (*FSCache, error) {
+	for _, sub := range []string{"objects", "index"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), cacheDirPerm); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+		}
+	}
+
+	return &FSCache{dir: dir}, nil
+}
+
+// Get implements Cache. A missing or corrupt PDF or index file is reported as
+// a miss so that it is downloaded again.
+func (f *FSCache) Get(_ context.Context, date time.Time) (*CacheEntry, bool, error) {
+	index, err := os.ReadFile(f.indexPath(date))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache index: %w", err)
+	}
+
+	// An index cut short or otherwise damaged is rewritten by the next Put.
+	var entry CacheEntry
+	if err := json.Unmarshal(index, &entry); err != nil {
+		return nil, false, nil
+	}
+
+	if _, err := hex.DecodeString(entry.SHA256); err != nil || len(entry.SHA256) != hex.EncodedLen(sha256.Size) {
+		return nil, false, nil
+	}
+
+	content, err := os.ReadFile(f.objectPath(entry.SHA256))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cached PDF: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return nil, false, nil
+	}
+
+	entry.Content = content
+	return &entry, true, nil
+}
+
+// Put implements Cache.
+func (f *FSCache) Put(_ context.Context, entry *CacheEntry) error {
+	if err := fsutil.WriteFileAtomic(f.objectPath(entry.SHA256), entry.Content); err != nil {
+		return fmt.Errorf("failed to write cached PDF: %w", err)
+	}
+
+	index, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache index: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(f.indexPath(entry.Date), index); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FSCache) indexPath(date time.Time) string {
+	return filepath.Join(f.dir, "index", date.Format("2006-01-02")+".json")
+}
+
+func (f *FSCache) objectPath(sha string) string {
+	return filepath.Join(f.dir, "objects", sha+".pdf")
+}
@@ -0,0 +1,243 @@
+package sbpfx_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/vcr"
+)
+
+func TestFSCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := sbpfx.NewFSCache(dir)
+	assert.NoError(t, err)
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+	client := fixtures.client(sbpfx.WithCache(cache))
+
+	first, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	// The second lookup, and one from a new client sharing the directory, are
+	// served from disk.
+	second, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	reopened, err := sbpfx.NewFSCache(dir)
+	assert.NoError(t, err)
+	third, err := fixtures.client(sbpfx.WithCache(reopened)).GetExchangeRate(t.Context(), sbpfx.USD, sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	assert.Zero(t, fixtures.Requests())
+	assert.Equal(t, first.SHA256, second.SHA256)
+	assert.True(t, first.FetchedAt.Equal(second.FetchedAt))
+	assert.Equal(t, first.URL, third.URL)
+	assert.Equal(t, "281.8289", third.Ready)
+
+	// Missing sheets aren't cached.
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-28"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	_, ok, err := cache.Get(t.Context(), time.Date(2025, 8, 28, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFSCacheCorruptObject(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := sbpfx.NewFSCache(dir)
+	assert.NoError(t, err)
+
+	date := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	err = cache.Put(t.Context(), &sbpfx.CacheEntry{
+		Date:         date,
+		URL:          "https://example.com/27-Aug-25.pdf",
+		SHA256:       "9b0d1b4fa6d1a4b0c6a4f0d69fb4c34be3e1a2f1e1bb0aba0e0f8b3c0e0d2a11",
+		FetchedAt:    time.Now(),
+		CheckedAt:    time.Now(),
+		ChangedAt:    time.Now(),
+		ETag:         "",
+		LastModified: "",
+		Content:      []byte("%PDF-1.4"),
+	})
+	assert.NoError(t, err)
+
+	// The stored bytes don't match the recorded hash, so it's a miss.
+	_, ok, err := cache.Get(t.Context(), date)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	objects, err := os.ReadDir(filepath.Join(dir, "objects"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(objects))
+}
+
+func TestFSCacheCorruptIndex(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := sbpfx.NewFSCache(dir)
+	assert.NoError(t, err)
+
+	// An index file cut short, e.g. by a disk filling up, is a miss.
+	err = os.WriteFile(filepath.Join(dir, "index", "2025-08-27.json"), []byte(`{"date":"2025-08-27T00:00`), 0o600)
+	assert.NoError(t, err)
+
+	_, ok, err := cache.Get(t.Context(), time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSheetCache(t *testing.T) {
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates", "TestGetExchangeRatesDualFormat")
+	client := fixtures.client(sbpfx.WithSheetCache(1))
+
+	first, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	// Changing a returned sheet doesn't change what the cache hands out.
+	first.Rates[0].Ready = "0"
+
+	rate, err := client.GetExchangeRate(t.Context(), sbpfx.EUR, sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, "326.9215", rate.Ready)
+	usd, err := client.GetExchangeRate(t.Context(), sbpfx.USD, sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, "281.8289", usd.Ready)
+	assert.Zero(t, fixtures.Requests())
+
+	// A second date evicts the first from a cache of one.
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	fixtures.Requests()
+
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+}
+
+// readOnlyCache is a Cache that holds nothing and fails every Put.
+type readOnlyCache struct{}
+
+func (readOnlyCache) Get(context.Context, time.Time) (*sbpfx.CacheEntry, bool, error) {
+	return nil, false, nil
+}
+
+func (readOnlyCache) Put(context.Context, *sbpfx.CacheEntry) error {
+	return errors.New("read-only file system")
+}
+
+func TestCachePutFailure(t *testing.T) {
+	client, records := logRecords(t, "TestGetExchangeRates", sbpfx.WithCache(readOnlyCache{}))
+
+	// The downloaded sheet is still returned, and the failure logged.
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-08-27", sheet.Date.Format("2006-01-02"))
+
+	logged := records()
+	i := slices.Index(messages(logged), "WARN failed to cache rate sheet")
+	assert.NotEqual(t, -1, i)
+	assert.Equal(t, "read-only file system", logged[i]["error"])
+}
+
+func TestCacheRevalidatesToday(t *testing.T) {
+	cache, err := sbpfx.NewFSCache(t.TempDir())
+	assert.NoError(t, err)
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates", "TestGetExchangeRatesDualFormat")
+	client := fixtures.client(sbpfx.WithCache(cache))
+
+	// Serve the recorded 2025-08-27 sheet as today's.
+	today, err := url.Parse(client.GetUrl())
+	assert.NoError(t, err)
+	recorded, ok := fixtures.Response("/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf")
+	assert.True(t, ok)
+	fixtures.Serve(today.Path, recorded)
+
+	first, err := client.GetRateSheet(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	cached, ok, err := cache.Get(t.Context(), first.Date)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NotZero(t, cached.LastModified)
+
+	// Polling again asks SBP whether the sheet changed, and it hasn't.
+	second, err := client.GetRateSheet(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{today.Path}, fixtures.Requests())
+	assert.Equal(t, 1, fixtures.NotModified())
+	assert.Equal(t, first.SHA256, second.SHA256)
+
+	revalidated, _, err := cache.Get(t.Context(), first.Date)
+	assert.NoError(t, err)
+	assert.True(t, revalidated.CheckedAt.After(cached.CheckedAt))
+	assert.True(t, revalidated.ChangedAt.Equal(cached.ChangedAt))
+
+	// SBP re-posts a corrected sheet.
+	corrected, ok := fixtures.Response("/assets/document/17-Jul-26.pdf")
+	assert.True(t, ok)
+	fixtures.Serve(today.Path, corrected)
+
+	third, err := client.GetRateSheet(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+	assert.Equal(t, 1, fixtures.NotModified())
+	assert.NotEqual(t, first.SHA256, third.SHA256)
+
+	changed, _, err := cache.Get(t.Context(), first.Date)
+	assert.NoError(t, err)
+	assert.True(t, changed.ChangedAt.After(cached.ChangedAt))
+}
+
+func TestCacheRevalidationFailure(t *testing.T) {
+	cache, err := sbpfx.NewFSCache(t.TempDir())
+	assert.NoError(t, err)
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+	var down atomic.Bool
+	transport := vcr.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if down.Load() {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Source: nil, Addr: nil, Err: syscall.ECONNREFUSED}
+		}
+		return fixtures.Transport().RoundTrip(r)
+	})
+	client := fixtures.client(sbpfx.WithCache(cache), httpr.HTTPClient(http.Client{Transport: transport}))
+
+	// Serve the recorded 2025-08-27 sheet as today's.
+	today, err := url.Parse(client.GetUrl())
+	assert.NoError(t, err)
+	recorded, ok := fixtures.Response("/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf")
+	assert.True(t, ok)
+	fixtures.Serve(today.Path, recorded)
+
+	first, err := client.GetRateSheet(t.Context())
+	assert.NoError(t, err)
+	cached, _, err := cache.Get(t.Context(), first.Date)
+	assert.NoError(t, err)
+
+	// SBP can't be reached, so the cached sheet is served without searching
+	// the other candidates, and isn't marked as checked.
+	down.Store(true)
+	second, err := client.GetRateSheet(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, first.SHA256, second.SHA256)
+
+	unchecked, _, err := cache.Get(t.Context(), first.Date)
+	assert.NoError(t, err)
+	assert.True(t, unchecked.CheckedAt.Equal(cached.CheckedAt))
+}
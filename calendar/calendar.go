@@ -0,0 +1,197 @@
+// Package calendar models the Pakistani business-day calendar that the State
+// Bank of Pakistan publishes rate sheets on: weekends, fixed public holidays,
+// and a table of lunar holidays (Eid, Ashura) whose dates depend on moon
+// sighting and are announced each year.
+//
+// Only the year, month and day of a time.Time are considered, in whatever
+// location the time carries; callers pass business dates, not instants.
+package calendar
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	maxGapDays = 366 // give up searching for a business day after a year
+)
+
+// builtinHolidays is the lunar holiday table shipped with the package. Lunar
+// dates depend on moon sighting, so it may be off by a day for years that had
+// not been notified when it was written; override it with LoadHolidays.
+//
+//go:embed holidays.json
+var builtinHolidays []byte
+
+// Holiday is a single non-business day.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// holidayEntry is the on-disk form of a Holiday read by LoadHolidays.
+type holidayEntry struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+type monthDay struct {
+	month time.Month
+	day   int
+}
+
+// Calendar decides which days are business days. It is safe for concurrent
+// use.
+type Calendar struct {
+	mu       sync.RWMutex
+	weekend  map[time.Weekday]bool
+	fixed    map[monthDay]string // holidays on the same date every year
+	holidays map[string]string   // one-off holidays keyed by YYYY-MM-DD
+}
+
+// New returns a calendar with the given weekend days and no holidays. With no
+// weekend days every day is a business day.
+func New(weekend ...time.Weekday) *Calendar {
+	c := &Calendar{
+		mu:       sync.RWMutex{},
+		weekend:  make(map[time.Weekday]bool, len(weekend)),
+		fixed:    make(map[monthDay]string),
+		holidays: make(map[string]string),
+	}
+	for _, day := range weekend {
+		c.weekend[day] = true
+	}
+
+	return c
+}
+
+// Pakistan returns the calendar SBP follows: Saturday and Sunday weekends, the
+// fixed public holidays, and the built-in lunar holiday table.
+func Pakistan() *Calendar {
+	c := New(time.Saturday, time.Sunday)
+
+	c.AddFixedHoliday(time.February, 5, "Kashmir Solidarity Day")
+	c.AddFixedHoliday(time.March, 23, "Pakistan Day")
+	c.AddFixedHoliday(time.May, 1, "Labour Day")
+	c.AddFixedHoliday(time.May, 28, "Youm-e-Takbeer")
+	c.AddFixedHoliday(time.August, 14, "Independence Day")
+	c.AddFixedHoliday(time.November, 9, "Iqbal Day")
+	c.AddFixedHoliday(time.December, 25, "Quaid-e-Azam Day")
+
+	if err := c.LoadHolidays(bytes.NewReader(builtinHolidays)); err != nil {
+		panic(fmt.Sprintf("calendar: invalid built-in holiday table: %v", err))
+	}
+
+	return c
+}
+
+// AddFixedHoliday marks a date as a holiday in every year.
+func (c *Calendar) AddFixedHoliday(month time.Month, day int, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fixed[monthDay{month: month, day: day}] = name
+}
+
+// AddHoliday marks a single date as a holiday.
+func (c *Calendar) AddHoliday(date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holidays[date.Format(dateLayout)] = name
+}
+
+// LoadHolidays adds the one-off holidays in a JSON table to the calendar. The
+// table is an array of {"date": "YYYY-MM-DD", "name": "..."} objects, e.g. a
+// year's notified Eid and Ashura dates. Nothing is added if any entry is
+// invalid.
+func (c *Calendar) LoadHolidays(r io.Reader) error {
+	var entries []holidayEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode holiday table: %w", err)
+	}
+
+	holidays := make([]Holiday, 0, len(entries))
+	for _, entry := range entries {
+		date, err := time.Parse(dateLayout, entry.Date)
+		if err != nil {
+			return fmt.Errorf("invalid holiday date '%s', expected format: YYYY-MM-DD", entry.Date)
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: entry.Name})
+	}
+
+	for _, holiday := range holidays {
+		c.AddHoliday(holiday.Date, holiday.Name)
+	}
+
+	return nil
+}
+
+// Holiday returns the name of the holiday on date, if it is one.
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if name, ok := c.holidays[date.Format(dateLayout)]; ok {
+		return name, true
+	}
+
+	name, ok := c.fixed[monthDay{month: date.Month(), day: date.Day()}]
+	return name, ok
+}
+
+// IsBusinessDay reports whether date is neither a weekend day nor a holiday.
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	c.mu.RLock()
+	weekend := c.weekend[date.Weekday()]
+	c.mu.RUnlock()
+
+	if weekend {
+		return false
+	}
+
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// PreviousBusinessDay returns the closest business day strictly before date,
+// or the zero time if there is none within a year.
+func (c *Calendar) PreviousBusinessDay(date time.Time) time.Time {
+	return c.step(date, -1)
+}
+
+// NextBusinessDay returns the closest business day strictly after date, or the
+// zero time if there is none within a year.
+func (c *Calendar) NextBusinessDay(date time.Time) time.Time {
+	return c.step(date, 1)
+}
+
+// BusinessDaysBetween returns the business days from from through to,
+// inclusive, in ascending order. It returns nil if to is before from.
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) []time.Time {
+	var days []time.Time
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(date) {
+			days = append(days, date)
+		}
+	}
+
+	return days
+}
+
+func (c *Calendar) step(date time.Time, direction int) time.Time {
+	for range maxGapDays {
+		date = date.AddDate(0, 0, direction)
+		if c.IsBusinessDay(date) {
+			return date
+		}
+	}
+
+	return time.Time{}
+}
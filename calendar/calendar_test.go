@@ -0,0 +1,87 @@
+package calendar_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx/calendar"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	cal := calendar.Pakistan()
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-08-27", true},  // Wednesday
+		{"2025-08-23", false}, // Saturday
+		{"2025-08-24", false}, // Sunday
+		{"2025-08-14", false}, // Independence Day
+		{"2026-12-25", false}, // Quaid-e-Azam Day, every year
+		{"2025-04-01", false}, // Eid ul Fitr, from the lunar table
+		{"2026-04-01", true},  // same day, a year later, is not a holiday
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, cal.IsBusinessDay(date(tt.date)), "IsBusinessDay(%s)", tt.date)
+	}
+
+	name, ok := cal.Holiday(date("2025-08-14"))
+	assert.True(t, ok)
+	assert.Equal(t, "Independence Day", name)
+}
+
+func TestPreviousAndNextBusinessDay(t *testing.T) {
+	cal := calendar.Pakistan()
+
+	// Monday -> Friday across a weekend.
+	assert.Equal(t, date("2025-08-22"), cal.PreviousBusinessDay(date("2025-08-25")))
+	assert.Equal(t, date("2025-08-25"), cal.NextBusinessDay(date("2025-08-22")))
+
+	// Eid ul Fitr 2025 ran Monday to Wednesday, so the Thursday before the
+	// preceding weekend is the previous business day.
+	assert.Equal(t, date("2025-03-28"), cal.PreviousBusinessDay(date("2025-04-03")))
+
+	// With no weekend days and no holidays, every day is a business day.
+	assert.Equal(t, date("2025-08-24"), calendar.New().PreviousBusinessDay(date("2025-08-25")))
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	cal := calendar.Pakistan()
+
+	days := cal.BusinessDaysBetween(date("2025-08-11"), date("2025-08-18"))
+	assert.Equal(t, []time.Time{
+		date("2025-08-11"),
+		date("2025-08-12"),
+		date("2025-08-13"),
+		// 14th is Independence Day, 16th and 17th are the weekend
+		date("2025-08-15"),
+		date("2025-08-18"),
+	}, days)
+
+	assert.Zero(t, cal.BusinessDaysBetween(date("2025-08-18"), date("2025-08-11")))
+}
+
+func TestLoadHolidays(t *testing.T) {
+	cal := calendar.New(time.Saturday, time.Sunday)
+
+	err := cal.LoadHolidays(strings.NewReader(`[{"date": "2027-03-10", "name": "Eid ul Fitr"}]`))
+	assert.NoError(t, err)
+	assert.False(t, cal.IsBusinessDay(date("2027-03-10")))
+
+	// An invalid entry rejects the whole table.
+	err = cal.LoadHolidays(strings.NewReader(`[{"date": "2027-03-11", "name": "ok"}, {"date": "11/03/2027", "name": "bad"}]`))
+	assert.Error(t, err)
+	assert.True(t, cal.IsBusinessDay(date("2027-03-11")))
+}
@@ -0,0 +1,24 @@
+// Command sbpfx looks up State Bank of Pakistan exchange rates from the
+// command line.
+//
+//	sbpfx rate --currency USD
+//	sbpfx rates --date 2025-08-27 --format csv
+//	sbpfx convert --latest 7 1000 EUR USD
+//
+// Run "sbpfx help" for every command.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/mistermoe/sbpfx/internal/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,121 @@
+package sbpfx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Conversion is the result of converting an amount between two currencies at
+// one sheet's spot (Ready) rates.
+type Conversion struct {
+	From   Currency  `json:"from"`
+	To     Currency  `json:"to"`
+	Amount Rate      `json:"amount"` // Amount of From that was converted
+	Result Rate      `json:"result"` // Amount in To, rounded to the requested precision
+	Rate   Rate      `json:"rate"`   // Units of To per unit of From, rounded to the requested precision
+	Date   time.Time `json:"date"`   // Business date of the sheet the rates came from
+	URL    string    `json:"url"`    // Source PDF URL
+}
+
+// Cross returns the rate for from in units of to, e.g. Cross(EUR, USD) is the
+// number of US dollars per euro. Every rate on the sheet is quoted against PKR,
+// so the cross rate is the ratio of the two spot rates, computed exactly and
+// then rounded per the Rounding and Precision options; other options are
+// ignored. Either currency may be PKR.
+func (s *RateSheet) Cross(from, to Currency, opts ...Option) (Rate, error) {
+	cfg, err := conversionConfig(opts)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	fromRate, toRate, err := s.spotRates(from, to)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return fromRate.Div(toRate, cfg.precision, cfg.rounding), nil
+}
+
+// convert converts amount from one currency to another at the sheet's spot
+// rates. The result is computed exactly and rounded once, rather than from the
+// rounded cross rate.
+func (s *RateSheet) convert(amount Rate, from, to Currency, cfg *option) (*Conversion, error) {
+	fromRate, toRate, err := s.spotRates(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conversion{
+		From:   from,
+		To:     to,
+		Amount: amount,
+		Result: amount.Mul(fromRate).Div(toRate, cfg.precision, cfg.rounding),
+		Rate:   fromRate.Div(toRate, cfg.precision, cfg.rounding),
+		Date:   s.Date,
+		URL:    s.URL,
+	}, nil
+}
+
+// spotRates returns the PKR spot rates of two currencies, with PKR itself at 1.
+func (s *RateSheet) spotRates(from, to Currency) (Rate, Rate, error) {
+	fromRate, err := s.spotRate(from)
+	if err != nil {
+		return Rate{}, Rate{}, err
+	}
+
+	toRate, err := s.spotRate(to)
+	if err != nil {
+		return Rate{}, Rate{}, err
+	}
+
+	return fromRate, toRate, nil
+}
+
+func (s *RateSheet) spotRate(currency Currency) (Rate, error) {
+	if currency == PKR {
+		return NewRate(1, 0), nil
+	}
+
+	row, err := sheetRate(s, currency)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	ready, ok := row.ReadyRate()
+	if !ok || ready.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("no spot rate for %s on %s: %w", currency, s.Date.Format("2006-01-02"), ErrCurrencyNotFound)
+	}
+
+	return ready, nil
+}
+
+// Convert converts amount from one currency to another at the spot (Ready)
+// rates of the sheet selected by opts, triangulating through PKR. The result
+// carries the sheet's date and URL, which differ from the requested date after
+// a LatestAvailable fallback. Use Rounding and Precision to control rounding.
+func (c *Client) Convert(ctx context.Context, amount Rate, from, to Currency, opts ...Option) (*Conversion, error) {
+	cfg, err := c.config(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := c.rateSheet(ctx, cfg.date, cfg.lookback)
+	if err != nil {
+		return nil, err
+	}
+
+	return sheet.convert(amount, from, to, cfg)
+}
+
+// conversionConfig applies the options that Cross honours.
+func conversionConfig(opts []Option) (*option, error) {
+	cfg := defaultConfig(time.UTC)
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,56 @@
+package sbpfx_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+)
+
+func TestCross(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	// 326.9215 / 281.8289 = 1.159999914...
+	eurUSD, err := sheet.Cross(sbpfx.EUR, sbpfx.USD)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1600", eurUSD.String())
+
+	eurUSD, err = sheet.Cross(sbpfx.EUR, sbpfx.USD, sbpfx.Precision(8), sbpfx.Rounding(sbpfx.RoundDown))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.15999991", eurUSD.String())
+
+	usdPKR, err := sheet.Cross(sbpfx.USD, sbpfx.PKR)
+	assert.NoError(t, err)
+	assert.Equal(t, "281.8289", usdPKR.String())
+
+	_, err = sheet.Cross(sbpfx.GNH, sbpfx.USD)
+	assert.IsError(t, err, sbpfx.ErrCurrencyNotFound)
+
+	_, err = sheet.Cross(sbpfx.EUR, sbpfx.USD, sbpfx.Precision(-1))
+	assert.Error(t, err)
+	_, err = sheet.Cross(sbpfx.EUR, sbpfx.USD, sbpfx.Precision(sbpfx.MaxPrecision+1))
+	assert.EqualError(t, err, "failed to apply option: invalid precision 21, must be between 0 and 20")
+}
+
+func TestConvert(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	// The amount is converted exactly and rounded once: 1000 EUR is
+	// 1159.99991484... USD, not 1000 × the rounded 1.1600 cross rate.
+	conversion, err := client.Convert(t.Context(), mustRate(t, "1000"), sbpfx.EUR, sbpfx.USD,
+		sbpfx.ForDate("2025-08-29"), sbpfx.LatestAvailable(3), sbpfx.Precision(2), sbpfx.Rounding(sbpfx.RoundDown))
+	assert.NoError(t, err)
+	assert.Equal(t, "1159.99", conversion.Result.String())
+	assert.Equal(t, "1.15", conversion.Rate.String())
+	assert.Equal(t, "2025-08-27", conversion.Date.Format("2006-01-02"))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", conversion.URL)
+
+	conversion, err = client.Convert(t.Context(), mustRate(t, "100"), sbpfx.USD, sbpfx.PKR, sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, "28182.8900", conversion.Result.String())
+
+	_, err = client.Convert(t.Context(), mustRate(t, "100"), sbpfx.USD, sbpfx.GNH, sbpfx.ForDate("2025-08-27"))
+	assert.IsError(t, err, sbpfx.ErrCurrencyNotFound)
+}
@@ -0,0 +1,228 @@
+package sbpfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mistermoe/httpr"
+)
+
+const (
+	maxIndexSize = 8 << 20 // Bytes of an index page read at most
+	century      = 2000    // SBP's two-digit years are all 20YY
+)
+
+var (
+	// pdfLink matches the target of a link to a PDF.
+	pdfLink = regexp.MustCompile(`(?i)href\s*=\s*["']([^"'?#]+\.pdf(?:[?#][^"']*)?)["']`)
+
+	// sheetName matches a sheet's file name, capturing its date, in the forms
+	// SBP has used: the rate prefix followed by 27-Aug-25 or 14-july-2026, or a
+	// bare date such as 17-Jul-26 or 30-Jun-26_1. Other dated PDFs on the page,
+	// such as BPRD-Circular-05-Jan-26.pdf, don't match.
+	sheetName = regexp.MustCompile(`(?i)^(?:` + regexp.QuoteMeta(strings.TrimPrefix(ratePrefix, "/")) +
+		`-)?(\d{1,2})-([a-z]{3,9})-(\d{4}|\d{2})(?:_\d+)?\.pdf$`)
+)
+
+// DiscoveryResolver is a Resolver that reads where SBP posted each sheet off
+// an HTML page linking to them, such as SBP's listing of mark-to-market
+// sheets, instead of guessing names. It yields only the sheets the page links
+// to, so chain it ahead of DefaultResolver to fall back on guessing for the
+// rest:
+//
+//	discovery := sbpfx.NewDiscoveryResolver(indexURL, time.Hour)
+//	client := sbpfx.New(sbpfx.WithResolver(sbpfx.ChainResolvers(discovery, sbpfx.DefaultResolver())))
+//
+// Discovery is opt-in: New uses DefaultResolver alone, and sbpfx doesn't
+// build in the listing page's address. SBP has moved its documents between
+// hosts before, and a stale built-in address would cost every client a failed
+// request each time its copy expired, for nothing. Pass the page's current
+// URL yourself, as sbpfx serve does with --index.
+//
+// A sheet's date is read from its file name, so links the page labels
+// differently from their names are still found. It is safe for concurrent use.
+type DiscoveryResolver struct {
+	indexURL   string
+	ttl        time.Duration
+	httpClient *httpr.Client
+
+	mu        sync.Mutex
+	index     map[string][]string // Candidate URLs keyed by YYYY-MM-DD
+	fetchedAt time.Time
+	err       error         // Why the last read failed, while there is no index
+	loading   chan struct{} // Closed when the read in progress ends; nil if none
+}
+
+// NewDiscoveryResolver returns a resolver reading the page at indexURL, which
+// it reads again once its copy is older than ttl. options configure the HTTP
+// client it reads the page with, as for New.
+func NewDiscoveryResolver(indexURL string, ttl time.Duration, options ...httpr.ClientOption) *DiscoveryResolver {
+	return &DiscoveryResolver{
+		indexURL:   indexURL,
+		ttl:        ttl,
+		httpClient: httpr.NewClient(options...),
+		mu:         sync.Mutex{},
+		index:      nil,
+		fetchedAt:  time.Time{},
+		err:        nil,
+		loading:    nil,
+	}
+}
+
+// Resolve implements Resolver. It returns no candidates for a date the page
+// doesn't link to. If the page can't be read, the copy read last is used, and
+// an error is returned only if there is none.
+func (r *DiscoveryResolver) Resolve(ctx context.Context, date time.Time) ([]string, error) {
+	index, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(index[date.Format("2006-01-02")]), nil
+}
+
+// load returns the index, reading the page again if the copy has expired.
+// Concurrent callers share a single read, which is made without holding r.mu
+// so that callers with a fresh copy aren't held up by it.
+func (r *DiscoveryResolver) load(ctx context.Context) (map[string][]string, error) {
+	for {
+		r.mu.Lock()
+		if r.index != nil && time.Since(r.fetchedAt) < r.ttl {
+			index := r.index
+			r.mu.Unlock()
+			return index, nil
+		}
+		if r.loading == nil {
+			break
+		}
+
+		loading := r.loading
+		r.mu.Unlock()
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		r.mu.Lock()
+		index, err := r.index, r.err
+		r.mu.Unlock()
+		switch {
+		case index != nil:
+			return index, nil
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			continue // The caller that read the page gave up; read it again.
+		default:
+			return nil, err
+		}
+	}
+
+	loading := make(chan struct{})
+	r.loading = loading
+	r.mu.Unlock()
+
+	index, err := r.fetch(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loading = nil
+	close(loading)
+
+	if err != nil {
+		if r.index == nil {
+			r.err = err
+			return nil, err
+		}
+		// Keep the stale copy, and don't try again until it would have
+		// expired, so an outage doesn't cost a request per lookup.
+		index = r.index
+	}
+
+	r.index, r.fetchedAt, r.err = index, time.Now(), nil
+	return index, nil
+}
+
+func (r *DiscoveryResolver) fetch(ctx context.Context) (map[string][]string, error) {
+	base, err := url.Parse(r.indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sheet index URL %q: %w", r.indexURL, err)
+	}
+
+	resp, err := r.httpClient.Get(ctx, r.indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet index %s: %w", r.indexURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read sheet index %s: status %d", r.indexURL, resp.StatusCode)
+	}
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet index %s: %w", r.indexURL, err)
+	}
+
+	return parseIndex(base, page), nil
+}
+
+// parseIndex maps each date to the sheets a page links to, in page order,
+// resolving links against base.
+func parseIndex(base *url.URL, page []byte) map[string][]string {
+	index := map[string][]string{}
+	for _, match := range pdfLink.FindAllSubmatch(page, -1) {
+		link, err := base.Parse(html.UnescapeString(string(match[1])))
+		if err != nil {
+			continue
+		}
+		date, ok := sheetDate(path.Base(link.Path))
+		if !ok {
+			continue
+		}
+
+		key := date.Format("2006-01-02")
+		if !slices.Contains(index[key], link.String()) {
+			index[key] = append(index[key], link.String())
+		}
+	}
+	return index
+}
+
+// sheetDate returns the date in a sheet's file name.
+func sheetDate(name string) (time.Time, bool) {
+	match := sheetName.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	day, _ := strconv.Atoi(match[1])
+	year, _ := strconv.Atoi(match[3])
+	if year < YearModulo {
+		year += century
+	}
+
+	for month := time.January; month <= time.December; month++ {
+		full := month.String()
+		if !strings.EqualFold(match[2], full) && !strings.EqualFold(match[2], full[:3]) {
+			continue
+		}
+
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if date.Day() != day || date.Month() != month {
+			return time.Time{}, false // e.g. 31-Jun
+		}
+		return date, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,119 @@
+package sbpfx_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/vcr"
+	"gopkg.in/dnaeon/go-vcr.v3/cassette"
+)
+
+const indexURL = "https://index.example/m2m/rates.html"
+
+// serveIndex adds the sheet-index.html fixture to the fixture server at
+// indexURL.
+func serveIndex(t *testing.T, fixtures *fixtureServer) {
+	t.Helper()
+
+	page, err := os.ReadFile("fixtures/sheet-index.html")
+	assert.NoError(t, err)
+
+	var resp cassette.Response
+	resp.Code = http.StatusOK
+	resp.Headers = http.Header{"Content-Type": {"text/html; charset=utf-8"}}
+	resp.Body = string(page)
+	fixtures.Serve("/m2m/rates.html", resp)
+}
+
+func TestDiscoveryResolver(t *testing.T) {
+	_, fixtures := newFixtureClient(t)
+	serveIndex(t, fixtures)
+	resolver := sbpfx.NewDiscoveryResolver(indexURL, time.Hour, httpr.HTTPClient(http.Client{Transport: fixtures.Transport()}))
+
+	const base = "https://www.sbp.org.pk/assets/document"
+	tests := map[string][]string{
+		"2026-07-17": {base + "/17-Jul-26.pdf"},
+		"2026-07-16": {base + "/mark-to-market-revaluation-exchange-rate-16-july-2026.pdf"},
+		"2026-06-30": {"https://index.example/assets/document/30-Jun-26_1.pdf"},
+		"2025-08-27": {"https://index.example/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf?v=1&x=2"},
+		"2026-07-15": nil,
+	}
+	for date, want := range tests {
+		assert.Equal(t, want, resolve(t, resolver, date), date)
+	}
+	// The page is read once and cached.
+	assert.Equal(t, []string{"/m2m/rates.html"}, fixtures.Requests())
+}
+
+func TestDiscoveryResolverClient(t *testing.T) {
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+	serveIndex(t, fixtures)
+	transport := httpr.HTTPClient(http.Client{Transport: fixtures.Transport()})
+	discovery := sbpfx.NewDiscoveryResolver(indexURL, time.Hour, transport)
+	client := fixtures.client(sbpfx.WithResolver(sbpfx.ChainResolvers(discovery, sbpfx.DefaultResolver())))
+
+	// The listed bare name is tried before the guessed long name, which would
+	// have been a soft 404.
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/17-Jul-26.pdf", sheet.URL)
+	assert.Equal(t, []string{"/m2m/rates.html", "/assets/document/17-Jul-26.pdf"}, fixtures.Requests())
+}
+
+func TestDiscoveryResolverStale(t *testing.T) {
+	_, fixtures := newFixtureClient(t)
+	serveIndex(t, fixtures)
+	transport := httpr.HTTPClient(http.Client{Transport: fixtures.Transport()})
+
+	// With a zero TTL every lookup reads the page again; once it's gone, the
+	// last copy is used.
+	resolver := sbpfx.NewDiscoveryResolver(indexURL, 0, transport)
+	assert.Equal(t, 1, len(resolve(t, resolver, "2026-07-17")))
+	fixtures.Remove("/m2m/rates.html")
+	assert.Equal(t, 1, len(resolve(t, resolver, "2026-07-17")))
+	assert.Equal(t, 2, len(fixtures.Requests()))
+
+	// Without a copy, the failure is returned.
+	_, err := sbpfx.NewDiscoveryResolver(indexURL, time.Hour, transport).Resolve(t.Context(), time.Now())
+	assert.Error(t, err)
+}
+
+func TestDiscoveryResolverSharesRead(t *testing.T) {
+	_, fixtures := newFixtureClient(t)
+	serveIndex(t, fixtures)
+
+	// The page is held up until released.
+	arrived, release := make(chan struct{}, 1), make(chan struct{})
+	transport := vcr.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		arrived <- struct{}{}
+		<-release
+		return fixtures.Transport().RoundTrip(r)
+	})
+	resolver := sbpfx.NewDiscoveryResolver(indexURL, time.Hour, httpr.HTTPClient(http.Client{Transport: transport}))
+
+	const callers = 4
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Go(func() {
+			assert.Equal(t, 1, len(resolve(t, resolver, "2026-07-17")))
+		})
+	}
+	<-arrived
+
+	// A caller waiting on the read can still give up.
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := resolver.Resolve(ctx, time.Now())
+	assert.IsError(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"/m2m/rates.html"}, fixtures.Requests())
+}
@@ -0,0 +1,74 @@
+package sbpfx
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSheetNotFound means SBP has not published a rate sheet at a candidate
+	// URL: the request returned a non-200 status or a soft-404 page. Weekends,
+	// holidays and future dates all produce it.
+	ErrSheetNotFound = errors.New("PDF not found")
+
+	// ErrMalformedSheet means a PDF was published for the date but it is not a
+	// parseable rate sheet.
+	ErrMalformedSheet = errors.New("malformed rate sheet")
+
+	// ErrCurrencyNotFound means the rate sheet was fetched but does not quote
+	// the requested currency.
+	ErrCurrencyNotFound = errors.New("currency not found on rate sheet")
+
+	// ErrInvalidRate means a string is not a plain decimal number that
+	// ParseRate accepts.
+	ErrInvalidRate = errors.New("invalid rate")
+
+	// ErrInvalidRules means URL naming rules failed to parse or validate.
+	ErrInvalidRules = errors.New("invalid URL rules")
+)
+
+// CandidateError records why a single candidate URL did not yield a rate
+// sheet. It wraps ErrSheetNotFound when SBP has nothing at the URL, or the
+// underlying transport error when the request itself failed.
+type CandidateError struct {
+	URL        string // Candidate URL that was requested
+	StatusCode int    // HTTP status code, or 0 if no response was received
+	Err        error  // Why the candidate was rejected
+}
+
+func (e *CandidateError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CandidateError) Unwrap() error {
+	return e.Err
+}
+
+// LookupError reports that none of the candidate URLs for a date yielded a
+// rate sheet. errors.Is sees through it to each candidate's failure, so
+// errors.Is(err, ErrSheetNotFound) holds when SBP had nothing at one or more
+// of the URLs; other candidates may have failed for other reasons, as
+// recorded in Failures.
+type LookupError struct {
+	Date     time.Time         // Business date that was looked up
+	URLs     []string          // Candidate URLs tried, in priority order
+	Failures []*CandidateError // Why each candidate was rejected, in the same order
+}
+
+func (e *LookupError) Error() string {
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reasons = append(reasons, failure.Error())
+	}
+
+	return "no rate sheet for " + e.Date.Format("2006-01-02") + ": " + strings.Join(reasons, "; ")
+}
+
+func (e *LookupError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		errs = append(errs, failure)
+	}
+	return errs
+}
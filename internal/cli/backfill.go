@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/calendar"
+	"github.com/mistermoe/sbpfx/internal/fsutil"
+)
+
+const (
+	archiveDirPerm  = 0o750
+	archiveFilePerm = 0o600
+	manifestName    = "manifest.jsonl"
+)
+
+// Statuses recorded in a backfill manifest.
+const (
+	statusOK        = "ok"        // Downloaded and parsed
+	statusMalformed = "malformed" // Downloaded, but not a parseable rate sheet
+	statusNotFound  = "not_found" // No sheet was published; retried on the next run
+)
+
+// manifestEntry is one line of an archive's manifest.jsonl. Later lines for
+// the same date supersede earlier ones.
+type manifestEntry struct {
+	Date   string `json:"date"`
+	URL    string `json:"url,omitempty"` // Empty if the PDF was found on disk without a manifest line
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int    `json:"size"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (m *manifestEntry) row() []string {
+	return []string{m.Date, m.Status, strconv.Itoa(m.Size), m.SHA256, m.URL}
+}
+
+// runBackfill downloads every business day's sheet between --from and --to
+// into --dir as YYYY-MM-DD.pdf, recording each date in manifest.jsonl. Dates
+// whose PDF is already in the archive are skipped, so an interrupted run picks
+// up where it left off. Days with no sheet are recorded but retried next time.
+func runBackfill(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "backfill")
+	from := f.set.String("from", "", "first business date `YYYY-MM-DD` (required)")
+	to := f.set.String("to", "", "last business date `YYYY-MM-DD` (required)")
+	dir := f.set.String("dir", "", "archive `directory`, created if missing (required)")
+	if err := f.parse(args, 0); err != nil {
+		return err
+	}
+
+	if *from == "" || *to == "" || *dir == "" {
+		return fmt.Errorf("%w: --from, --to and --dir are required", errUsage)
+	}
+	start, err := parseDate("from", *from)
+	if err != nil {
+		return err
+	}
+	end, err := parseDate("to", *to)
+	if err != nil {
+		return err
+	}
+	if end.Before(start) {
+		return fmt.Errorf("%w: --to %s is before --from %s", errUsage, *to, *from)
+	}
+
+	if err := os.MkdirAll(*dir, archiveDirPerm); err != nil {
+		return fmt.Errorf("failed to create archive directory %s: %w", *dir, err)
+	}
+	done, err := readManifest(filepath.Join(*dir, manifestName))
+	if err != nil {
+		return err
+	}
+	manifest, err := openManifest(filepath.Join(*dir, manifestName))
+	if err != nil {
+		return err
+	}
+	defer manifest.Close()
+
+	client := e.client()
+	out := &view{header: []string{"DATE", "STATUS", "SIZE", "SHA256", "URL"}, rows: nil, value: nil}
+	entries := []*manifestEntry{}
+	var failures []error
+	skipped := 0
+
+	for _, day := range calendar.Pakistan().BusinessDaysBetween(start, end) {
+		if err := ctx.Err(); err != nil {
+			failures = append(failures, err)
+			break
+		}
+
+		date := day.Format("2006-01-02")
+		path := filepath.Join(*dir, date+".pdf")
+		if entry, ok := done[date]; ok && entry.Status != statusNotFound && fileExists(path) {
+			skipped++
+			continue
+		}
+
+		entry, err := backfillDate(ctx, client, day, path)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "sbpfx backfill: %s: %v\n", date, err)
+			failures = append(failures, err)
+			continue
+		}
+		if err := manifest.append(entry); err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+		out.rows = append(out.rows, entry.row())
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(e.stderr, "sbpfx backfill: skipped %d date(s) already in %s\n", skipped, *dir)
+	}
+
+	out.value = entries
+	if err := out.write(e.stdout, f.format); err != nil {
+		return err
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d date(s) failed: %w", len(failures), errors.Join(failures...))
+	}
+	return nil
+}
+
+// backfillDate archives one day's sheet at path and returns its manifest
+// entry. A PDF already at path (left by a run interrupted before it updated
+// the manifest) is recorded as-is instead of being downloaded again. Errors
+// are only returned for failures worth retrying, such as network errors.
+func backfillDate(ctx context.Context, client *sbpfx.Client, day time.Time, path string) (*manifestEntry, error) {
+	entry := &manifestEntry{
+		Date:   day.Format("2006-01-02"),
+		URL:    "",
+		SHA256: "",
+		Size:   0,
+		Status: statusOK,
+		Error:  "",
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		fetched, err := client.FetchRateSheet(ctx, sbpfx.ForDate(entry.Date))
+		var netErr net.Error
+		switch {
+		case err == nil:
+		case errors.Is(err, sbpfx.ErrSheetNotFound) && !errors.As(err, &netErr):
+			entry.Status = statusNotFound
+			return entry, nil
+		default:
+			return nil, err
+		}
+
+		if err := fsutil.WriteFileAtomic(path, fetched.Content); err != nil {
+			return nil, err
+		}
+		content, entry.URL = fetched.Content, fetched.URL
+	}
+
+	sum := sha256.Sum256(content)
+	entry.SHA256 = hex.EncodeToString(sum[:])
+	entry.Size = len(content)
+	if _, err := sbpfx.ParseRateSheet(content, day, entry.URL); err != nil {
+		entry.Status = statusMalformed
+		entry.Error = err.Error()
+	}
+
+	return entry, nil
+}
+
+// readManifest returns the latest manifest entry for each date, or none if
+// the manifest doesn't exist yet. Lines that don't decode, such as one cut
+// short by an interrupted run, are ignored.
+func readManifest(path string) (map[string]*manifestEntry, error) {
+	entries := map[string]*manifestEntry{}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry manifestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Date == "" {
+			continue
+		}
+		entries[entry.Date] = &entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+// manifestFile appends entries to a manifest, one JSON object per line.
+type manifestFile struct {
+	file *os.File
+}
+
+// openManifest opens a manifest for appending, first terminating a final line
+// cut short by an interrupted run so the next entry starts on its own line.
+func openManifest(path string) (*manifestFile, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, archiveFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest %s: %w", path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to stat manifest %s: %w", path, err)
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+		}
+		if last[0] != '\n' {
+			if _, err := file.WriteString("\n"); err != nil {
+				_ = file.Close()
+				return nil, fmt.Errorf("failed to write manifest %s: %w", path, err)
+			}
+		}
+	}
+
+	return &manifestFile{file: file}, nil
+}
+
+func (m *manifestFile) append(entry *manifestEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest entry: %w", err)
+	}
+	if _, err := m.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", m.file.Name(), err)
+	}
+	return nil
+}
+
+func (m *manifestFile) Close() error {
+	return m.file.Close()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,212 @@
+// Package cli implements the sbpfx command-line tool.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+)
+
+// Exit codes returned by Run. Scripts can tell a missing sheet (try another
+// date) from a malformed one (report it) from a network failure (retry).
+const (
+	ExitOK        = 0 // Success
+	ExitError     = 1 // Any failure not covered below
+	ExitUsage     = 2 // Bad command line
+	ExitNotFound  = 3 // No sheet for the date, or the currency isn't on it
+	ExitMalformed = 4 // A sheet was published but couldn't be parsed
+	ExitNetwork   = 5 // SBP couldn't be reached
+)
+
+const usage = `Usage: sbpfx <command> [flags] [args]
+
+Commands:
+  rate      Print one currency's rates          sbpfx rate --currency USD
+  rates     Print every rate on a sheet         sbpfx rates --date 2025-08-27
+  url       Print the URL of a day's sheet      sbpfx url --date 2025-08-27
+  download  Save a day's sheet as a PDF         sbpfx download --date 2025-08-27 rates.pdf
+  convert   Convert an amount between currencies
+                                                sbpfx convert 1000 EUR USD
+  backfill  Archive every sheet in a date range as PDFs
+                                                sbpfx backfill --from 2025-01-01 --to 2025-12-31 --dir archive
+  serve     Serve rates over HTTP as JSON       sbpfx serve --addr :8080
+
+Flags go before arguments. Run "sbpfx <command> -h" for a command's flags.
+`
+
+// errUsage marks errors caused by a bad command line.
+var errUsage = errors.New("invalid usage")
+
+// Run runs the sbpfx command line args (without the program name), writing
+// output to stdout and diagnostics to stderr, and returns the exit code.
+// options configure the client, e.g. its HTTP transport.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, options ...httpr.ClientOption) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+
+	commands := map[string]func(context.Context, *env, []string) error{
+		"rate":     runRate,
+		"rates":    runRates,
+		"url":      runURL,
+		"download": runDownload,
+		"convert":  runConvert,
+		"backfill": runBackfill,
+		"serve":    runServe,
+	}
+
+	name, args := args[0], args[1:]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	}
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "sbpfx: unknown command %q\n\n%s", name, usage)
+		return ExitUsage
+	}
+
+	e := &env{stdout: stdout, stderr: stderr, options: options}
+	err := command(ctx, e, args)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	fmt.Fprintf(stderr, "sbpfx %s: %v\n", name, err)
+	return exitCode(err)
+}
+
+// exitCode maps an error to the exit code scripts should see. A network
+// failure wins over "not found", since a candidate that couldn't be reached
+// might have held the sheet.
+func exitCode(err error) int {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, sbpfx.ErrMalformedSheet):
+		return ExitMalformed
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return ExitNetwork
+	case errors.Is(err, sbpfx.ErrSheetNotFound), errors.Is(err, sbpfx.ErrCurrencyNotFound):
+		return ExitNotFound
+	default:
+		return ExitError
+	}
+}
+
+// env is what every command runs with.
+type env struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	options []httpr.ClientOption
+}
+
+func (e *env) client() *sbpfx.Client {
+	return sbpfx.New(e.options...)
+}
+
+// flags holds the flags shared by the lookup commands.
+type flags struct {
+	set      *flag.FlagSet
+	date     string
+	latest   int
+	currency string
+	format   string
+}
+
+// newFlags returns a flag set for a command with --format registered;
+// commands add their own flags before parsing.
+func newFlags(e *env, name string) *flags {
+	f := &flags{
+		set:      flag.NewFlagSet("sbpfx "+name, flag.ContinueOnError),
+		date:     "",
+		latest:   0,
+		currency: "",
+		format:   formatTable,
+	}
+	f.set.SetOutput(e.stderr)
+	f.set.StringVar(&f.format, "format", formatTable, "output format: table, json or csv")
+
+	return f
+}
+
+// dateFlags registers --date, and --latest if latest is set, for commands that
+// look up a single day's sheet.
+func (f *flags) dateFlags(latest bool) *flags {
+	f.set.StringVar(&f.date, "date", "", "business date `YYYY-MM-DD` (default today in Pakistan)")
+	if latest {
+		f.set.IntVar(&f.latest, "latest", 0, "fall back up to `N` days to the most recent published sheet")
+	}
+	return f
+}
+
+// parse parses args and checks that exactly nargs positional arguments remain.
+func (f *flags) parse(args []string, nargs int) error {
+	if err := f.set.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	if f.set.NArg() != nargs {
+		return fmt.Errorf("%w: expected %d argument(s), got %d", errUsage, nargs, f.set.NArg())
+	}
+
+	switch f.format {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		return fmt.Errorf("%w: unknown format %q, expected table, json or csv", errUsage, f.format)
+	}
+
+	if f.date != "" {
+		if _, err := parseDate("date", f.date); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseDate parses the YYYY-MM-DD value of the named flag.
+func parseDate(name, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid --%s %q, expected format: YYYY-MM-DD", errUsage, name, value)
+	}
+	return date, nil
+}
+
+// options turns --date and --latest into request options.
+func (f *flags) options() []sbpfx.Option {
+	var opts []sbpfx.Option
+	if f.date != "" {
+		opts = append(opts, sbpfx.ForDate(f.date))
+	}
+	if f.latest > 0 {
+		opts = append(opts, sbpfx.LatestAvailable(f.latest))
+	}
+	return opts
+}
+
+// parseCurrency parses a currency code, accepting PKR only if pkr is set.
+func parseCurrency(code string, pkr bool) (sbpfx.Currency, error) {
+	currency, err := sbpfx.ParseCurrency(code)
+	if err == nil && currency == sbpfx.PKR && !pkr {
+		err = fmt.Errorf("no rates are quoted for %s", currency)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errUsage, err)
+	}
+	return currency, nil
+}
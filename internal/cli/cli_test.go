@@ -0,0 +1,219 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx/internal/cli"
+	"github.com/mistermoe/sbpfx/vcr"
+)
+
+// fixtureTransport returns an HTTP client option that replays the named
+// cassettes with a vcr.Server.
+func fixtureTransport(t *testing.T, cassettes ...string) httpr.ClientOption {
+	t.Helper()
+
+	paths := make([]string, 0, len(cassettes))
+	for _, name := range cassettes {
+		paths = append(paths, filepath.Join("..", "..", "fixtures", name))
+	}
+	return httpr.HTTPClient(http.Client{Transport: vcr.NewServer(t, paths...).Transport()})
+}
+
+func run(t *testing.T, transport httpr.ClientOption, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(t.Context(), args, &stdout, &stderr, transport)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRate(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates")
+
+	code, stdout, _ := run(t, transport, "rate", "--date", "2025-08-27", "--currency", "usd")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "CURRENCY")
+	assert.Contains(t, stdout, "USD       2025-08-27  281.8289")
+
+	// --latest walks back from a day with no sheet.
+	code, stdout, _ = run(t, transport, "rate", "--date", "2025-08-29", "--latest", "3", "--currency", "EUR", "--format", "json")
+	assert.Equal(t, cli.ExitOK, code)
+
+	var rate struct {
+		Currency string `json:"currency"`
+		Ready    string `json:"ready"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &rate))
+	assert.Equal(t, "EUR", rate.Currency)
+	assert.Equal(t, "326.9215", rate.Ready)
+}
+
+func TestRates(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates")
+
+	code, stdout, _ := run(t, transport, "rates", "--date", "2025-08-27", "--currency", "USD,EUR", "--format", "csv")
+	assert.Equal(t, cli.ExitOK, code)
+
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, []string{"CURRENCY", "DATE", "READY", "1-WEEK"}, records[0][:4])
+	assert.Equal(t, []string{"USD", "2025-08-27", "281.8289", "282.0792"}, records[1][:4])
+	assert.Equal(t, "EUR", records[2][0])
+
+	code, stdout, _ = run(t, transport, "rates", "--date", "2025-08-27", "--format", "json")
+	assert.Equal(t, cli.ExitOK, code)
+
+	var sheet struct {
+		URL   string            `json:"url"`
+		Rates []json.RawMessage `json:"rates"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &sheet))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", sheet.URL)
+	assert.True(t, len(sheet.Rates) > 2)
+}
+
+func TestURLAndDownload(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates")
+
+	code, stdout, _ := run(t, transport, "url", "--date", "2025-08-27", "--format", "csv")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Equal(t, "URL\nhttps://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf\n", stdout)
+
+	path := filepath.Join(t.TempDir(), "rates.pdf")
+	code, _, _ = run(t, transport, "download", "--date", "2025-08-27", path)
+	assert.Equal(t, cli.ExitOK, code)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(content, []byte("%PDF")))
+}
+
+func TestConvert(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates")
+
+	code, stdout, _ := run(t, transport, "convert", "--date", "2025-08-27", "--precision", "2", "--format", "csv", "1000", "EUR", "USD")
+	assert.Equal(t, cli.ExitOK, code)
+
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "USD", "1000", "1160.00", "1.16", "2025-08-27"}, records[1][:6])
+}
+
+type manifestEntry struct {
+	Date   string `json:"date"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+	Status string `json:"status"`
+}
+
+func backfill(t *testing.T, transport httpr.ClientOption, args ...string) ([]manifestEntry, string) {
+	t.Helper()
+
+	code, stdout, stderr := run(t, transport, append([]string{"backfill", "--format", "json"}, args...)...)
+	assert.Equal(t, cli.ExitOK, code, stderr)
+
+	var entries []manifestEntry
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &entries))
+	return entries, stderr
+}
+
+func TestBackfill(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates", "TestGetExchangeRatesMalformedSheet")
+	dir := t.TempDir()
+
+	entries, _ := backfill(t, transport, "--from", "2025-08-26", "--to", "2025-08-28", "--dir", dir)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, manifestEntry{Date: "2025-08-26", URL: "", SHA256: "", Size: 0, Status: "not_found"}, entries[0])
+	assert.Equal(t, "ok", entries[1].Status)
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", entries[1].URL)
+	assert.Equal(t, 64, len(entries[1].SHA256))
+	assert.Equal(t, "not_found", entries[2].Status)
+
+	content, err := os.ReadFile(filepath.Join(dir, "2025-08-27.pdf"))
+	assert.NoError(t, err)
+	assert.Equal(t, len(content), entries[1].Size)
+
+	// A second run skips the archived sheet and retries the missing days.
+	entries, stderr := backfill(t, transport, "--from", "2025-08-26", "--to", "2025-08-28", "--dir", dir)
+	assert.Equal(t, []string{"2025-08-26", "2025-08-28"}, []string{entries[0].Date, entries[1].Date})
+	assert.Contains(t, stderr, "skipped 1 date(s)")
+
+	entries, _ = backfill(t, transport, "--from", "2026-06-01", "--to", "2026-06-01", "--dir", dir)
+	assert.Equal(t, "malformed", entries[0].Status)
+	assert.True(t, entries[0].Size > 0)
+
+	manifest, err := os.ReadFile(filepath.Join(dir, "manifest.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, strings.Count(string(manifest), "\n"))
+}
+
+func TestBackfillResume(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates")
+	dir := t.TempDir()
+
+	// Simulate a run interrupted after saving a PDF but partway through
+	// recording it in the manifest.
+	code, _, _ := run(t, transport, "download", "--date", "2025-08-27", filepath.Join(dir, "2025-08-27.pdf"))
+	assert.Equal(t, cli.ExitOK, code)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.jsonl"), []byte(`{"date":"2025-08-27","ur`), 0o600))
+
+	entries, _ := backfill(t, transport, "--from", "2025-08-27", "--to", "2025-08-27", "--dir", dir)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "ok", entries[0].Status)
+	assert.Equal(t, "", entries[0].URL)
+
+	entries, stderr := backfill(t, transport, "--from", "2025-08-27", "--to", "2025-08-27", "--dir", dir)
+	assert.Equal(t, 0, len(entries))
+	assert.Contains(t, stderr, "skipped 1 date(s)")
+}
+
+func TestExitCodes(t *testing.T) {
+	transport := fixtureTransport(t, "TestGetExchangeRates", "TestGetExchangeRatesMalformedSheet")
+	unreachable := httpr.HTTPClient(http.Client{Transport: vcr.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	})})
+
+	tests := []struct {
+		name      string
+		transport httpr.ClientOption
+		args      []string
+		want      int
+	}{
+		{"no command", transport, nil, cli.ExitUsage},
+		{"unknown command", transport, []string{"rats"}, cli.ExitUsage},
+		{"missing currency", transport, []string{"rate"}, cli.ExitUsage},
+		{"unknown currency", transport, []string{"rate", "--currency", "XYZ"}, cli.ExitUsage},
+		{"bad date", transport, []string{"rates", "--date", "27/08/2025"}, cli.ExitUsage},
+		{"bad format", transport, []string{"rates", "--format", "xml"}, cli.ExitUsage},
+		{"bad amount", transport, []string{"convert", "1e3", "EUR", "USD"}, cli.ExitUsage},
+		{"backfill without dir", transport, []string{"backfill", "--from", "2025-08-27", "--to", "2025-08-27"}, cli.ExitUsage},
+		{"backfill backwards", transport, []string{"backfill", "--from", "2025-08-27", "--to", "2025-08-26", "--dir", "archive"}, cli.ExitUsage},
+		{"serve with arguments", transport, []string{"serve", "extra"}, cli.ExitUsage},
+		{"serve with missing rules", transport, []string{"serve", "--rules", filepath.Join(t.TempDir(), "rules.yaml")}, cli.ExitUsage},
+		{"help", transport, []string{"rate", "-h"}, cli.ExitOK},
+		{"no sheet", transport, []string{"rates", "--date", "2025-08-28"}, cli.ExitNotFound},
+		{"currency not on sheet", transport, []string{"rate", "--date", "2025-08-27", "--currency", "GNH"}, cli.ExitNotFound},
+		{"malformed sheet", transport, []string{"rates", "--date", "2026-06-01"}, cli.ExitMalformed},
+		{"network", unreachable, []string{"rates", "--date", "2025-08-27"}, cli.ExitNetwork},
+		{"backfill network", unreachable, []string{"backfill", "--from", "2025-08-27", "--to", "2025-08-27", "--dir", t.TempDir()}, cli.ExitNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := run(t, tt.transport, tt.args...)
+			assert.Equal(t, tt.want, code, stderr)
+		})
+	}
+}
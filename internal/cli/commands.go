@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mistermoe/sbpfx"
+)
+
+// runRate prints one currency's rates.
+func runRate(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "rate").dateFlags(true)
+	f.set.StringVar(&f.currency, "currency", "", "currency `code`, e.g. USD (required)")
+	if err := f.parse(args, 0); err != nil {
+		return err
+	}
+
+	if f.currency == "" {
+		return fmt.Errorf("%w: --currency is required", errUsage)
+	}
+	currency, err := parseCurrency(f.currency, false)
+	if err != nil {
+		return err
+	}
+
+	rate, err := e.client().GetExchangeRate(ctx, currency, f.options()...)
+	if err != nil {
+		return err
+	}
+
+	out := &view{header: rateHeader(), rows: [][]string{rateRow(rate)}, value: rate}
+	return out.write(e.stdout, f.format)
+}
+
+// runRates prints every rate on a sheet, or only the --currency ones.
+func runRates(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "rates").dateFlags(true)
+	f.set.StringVar(&f.currency, "currency", "", "comma-separated currency `codes` to include (default all)")
+	if err := f.parse(args, 0); err != nil {
+		return err
+	}
+
+	var only map[sbpfx.Currency]bool
+	if f.currency != "" {
+		only = map[sbpfx.Currency]bool{}
+		for code := range strings.SplitSeq(f.currency, ",") {
+			currency, err := parseCurrency(code, false)
+			if err != nil {
+				return err
+			}
+			only[currency] = true
+		}
+	}
+
+	sheet, err := e.client().GetRateSheet(ctx, f.options()...)
+	if err != nil {
+		return err
+	}
+
+	if only != nil {
+		rates := sheet.Rates[:0:0]
+		for _, rate := range sheet.Rates {
+			if only[rate.Currency] {
+				rates = append(rates, rate)
+			}
+		}
+		sheet.Rates = rates
+	}
+
+	out := &view{header: rateHeader(), rows: nil, value: sheet}
+	for _, rate := range sheet.Rates {
+		out.rows = append(out.rows, rateRow(rate))
+	}
+	return out.write(e.stdout, f.format)
+}
+
+// runURL prints the URL of a day's sheet.
+func runURL(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "url").dateFlags(false)
+	if err := f.parse(args, 0); err != nil {
+		return err
+	}
+
+	url, err := e.client().GetUrlContext(ctx, f.options()...)
+	if err != nil {
+		return err
+	}
+	out := &view{header: []string{"URL"}, rows: [][]string{{url}}, value: map[string]string{"url": url}}
+	return out.write(e.stdout, f.format)
+}
+
+// runDownload saves a day's sheet to the path given as its argument.
+func runDownload(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "download").dateFlags(false)
+	if err := f.parse(args, 1); err != nil {
+		return err
+	}
+	path := f.set.Arg(0)
+
+	if err := e.client().DownloadRateSheet(ctx, path, f.options()...); err != nil {
+		return err
+	}
+
+	out := &view{
+		header: []string{"PATH"},
+		rows:   [][]string{{path}},
+		value:  map[string]string{"path": path},
+	}
+	return out.write(e.stdout, f.format)
+}
+
+// runConvert converts AMOUNT of FROM into TO at a sheet's spot rates.
+func runConvert(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "convert").dateFlags(true)
+	precision := f.set.Int("precision", 4, "decimal `places` in the result") //nolint:mnd // SBP's precision
+	rounding := f.set.String("rounding", "half-even", "rounding `mode`: half-even, half-up, half-down, down, up, floor or ceiling")
+	if err := f.parse(args, 3); err != nil { //nolint:mnd // AMOUNT FROM TO
+		return err
+	}
+
+	amount, err := sbpfx.ParseRate(f.set.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	from, err := parseCurrency(f.set.Arg(1), true)
+	if err != nil {
+		return err
+	}
+	to, err := parseCurrency(f.set.Arg(2), true)
+	if err != nil {
+		return err
+	}
+	mode, err := sbpfx.ParseRoundingMode(*rounding)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	opts := append(f.options(), sbpfx.Precision(*precision), sbpfx.Rounding(mode))
+	conversion, err := e.client().Convert(ctx, amount, from, to, opts...)
+	if err != nil {
+		return err
+	}
+
+	out := &view{
+		header: []string{"FROM", "TO", "AMOUNT", "RESULT", "RATE", "DATE", "URL"},
+		rows: [][]string{{
+			conversion.From.String(),
+			conversion.To.String(),
+			conversion.Amount.String(),
+			conversion.Result.String(),
+			conversion.Rate.String(),
+			conversion.Date.Format("2006-01-02"),
+			conversion.URL,
+		}},
+		value: conversion,
+	}
+	return out.write(e.stdout, f.format)
+}
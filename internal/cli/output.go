@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mistermoe/sbpfx"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// view is a command's output, ready to be rendered in any format: header and
+// rows for table and CSV, value for JSON.
+type view struct {
+	header []string
+	rows   [][]string
+	value  any
+}
+
+func (v *view) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v.value); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+		return nil
+	case formatCSV:
+		out := csv.NewWriter(w)
+		if err := out.WriteAll(append([][]string{v.header}, v.rows...)); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd // two-space column gap
+		fmt.Fprintln(tw, strings.Join(v.header, "\t"))
+		for _, row := range v.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("failed to write table: %w", err)
+		}
+		return nil
+	}
+}
+
+// rateHeader is the header for rows of exchange rates: the currency, the date,
+// then every tenor in sheet order.
+func rateHeader() []string {
+	header := []string{"CURRENCY", "DATE"}
+	for _, tenor := range sbpfx.Tenors() {
+		header = append(header, tenor.String())
+	}
+	return header
+}
+
+func rateRow(rate *sbpfx.ExchangeRate) []string {
+	row := []string{rate.Currency.String(), rate.Date.Format("2006-01-02")}
+	for _, tenor := range sbpfx.Tenors() {
+		row = append(row, rate.Tenor(tenor))
+	}
+	return row
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	serveReadHeaderTimeout = 10 * time.Second
+	serveShutdownTimeout   = 10 * time.Second
+	rulesPollInterval      = 30 * time.Second
+	indexTTL               = 15 * time.Minute
+)
+
+// runServe serves the JSON API (see package server), and the client's
+// Prometheus metrics at /metrics, until the command is interrupted, then waits
+// for in-flight requests to finish. A --rules file is reloaded when it
+// changes, so a newly irregular sheet name can be fixed without a restart. An
+// --index page is consulted for where each sheet was posted before the rules
+// are.
+func runServe(ctx context.Context, e *env, args []string) error {
+	f := newFlags(e, "serve")
+	addr := f.set.String("addr", "localhost:8080", "`address` to listen on")
+	cacheDir := f.set.String("cache-dir", "", "cache downloaded PDFs in `dir` (default none)")
+	sheets := f.set.Int("sheet-cache", 256, "keep up to `N` parsed sheets in memory") //nolint:mnd // about a year of sheets
+	rulesPath := f.set.String("rules", "", "resolve sheet URLs with the YAML or JSON rules `file`, reloaded when it changes (default built-in)")
+	index := f.set.String("index", "", "look sheets up on the HTML listing page at `URL` before guessing their names")
+	if err := f.parse(args, 0); err != nil {
+		return err
+	}
+
+	reg := prometheus.NewRegistry()
+	options := append(slices.Clone(e.options), sbpfx.WithSheetCache(*sheets), sbpfx.WithMetrics(reg))
+	if *cacheDir != "" {
+		cache, err := sbpfx.NewFSCache(*cacheDir)
+		if err != nil {
+			return err
+		}
+		options = append(options, sbpfx.WithCache(cache))
+	}
+	resolver := sbpfx.DefaultResolver()
+	if *rulesPath != "" {
+		rules, err := sbpfx.LoadRulesResolver(*rulesPath)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+		go rules.Watch(ctx, rulesPollInterval, func(err error) {
+			fmt.Fprintf(e.stderr, "sbpfx serve: keeping previous rules: %v\n", err)
+		})
+		resolver = rules
+	}
+	if *index != "" {
+		resolver = sbpfx.ChainResolvers(sbpfx.NewDiscoveryResolver(*index, indexTTL, e.options...), resolver)
+	}
+	options = append(options, sbpfx.WithResolver(resolver))
+
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", server.New(sbpfx.New(options...)))
+	mux.Handle("GET /metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+	fmt.Fprintf(e.stderr, "sbpfx serve: listening on http://%s\n", listener.Addr())
+
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serveShutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to shut down: %w", err)
+		}
+		return nil
+	}
+}
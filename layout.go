@@ -0,0 +1,226 @@
+package sbpfx
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	lineTolerance = 2.0 // glyphs whose baselines differ by less than this (pt) share a line
+	cellGap       = 3.0 // a horizontal gap wider than this (pt) starts a new cell
+)
+
+// textCell is a run of horizontally adjacent glyphs on one line of a page,
+// e.g. a currency code, a rate, or a column header.
+type textCell struct {
+	text  string
+	left  float64
+	right float64
+}
+
+func (c textCell) center() float64 {
+	return (c.left + c.right) / 2 //nolint:mnd // midpoint
+}
+
+// textLine is one visual line of a page: the cells sharing a baseline, ordered
+// left to right.
+type textLine struct {
+	y     float64
+	cells []textCell
+}
+
+// extractLines rebuilds the visual lines of a page from the position of every
+// glyph, so callers can reason about rows and columns instead of the order in
+// which the PDF happens to emit its text. Lines are returned top to bottom.
+func extractLines(page pdf.Page) (lines []textLine, err error) {
+	// The PDF library panics on some malformed content streams.
+	defer func() {
+		if r := recover(); r != nil {
+			lines = nil
+			err = fmt.Errorf("failed to read page content: %v", r)
+		}
+	}()
+
+	return groupLines(page.Content().Text), nil
+}
+
+// groupLines groups glyphs into the visual lines they sit on, ordered top to
+// bottom, with each line split into cells.
+func groupLines(glyphs []pdf.Text) []textLine {
+	type glyphLine struct {
+		y      float64
+		glyphs []pdf.Text
+	}
+
+	var grouped []*glyphLine
+	for _, glyph := range glyphs {
+		var line *glyphLine
+		for _, candidate := range grouped {
+			if math.Abs(candidate.y-glyph.Y) < lineTolerance {
+				line = candidate
+				break
+			}
+		}
+		if line == nil {
+			line = &glyphLine{y: glyph.Y, glyphs: nil}
+			grouped = append(grouped, line)
+		}
+		line.glyphs = append(line.glyphs, glyph)
+	}
+
+	// PDF coordinates grow upwards, so the top of the page has the largest Y.
+	slices.SortFunc(grouped, func(a, b *glyphLine) int {
+		return cmp.Compare(b.y, a.y)
+	})
+
+	lines := make([]textLine, 0, len(grouped))
+	for _, line := range grouped {
+		slices.SortStableFunc(line.glyphs, func(a, b pdf.Text) int {
+			return cmp.Compare(a.X, b.X)
+		})
+
+		if cells := splitCells(line.glyphs); len(cells) > 0 {
+			lines = append(lines, textLine{y: line.y, cells: cells})
+		}
+	}
+
+	return lines
+}
+
+// splitCells groups a line's glyphs, sorted by X, into cells wherever the gap
+// between one glyph's right edge and the next glyph's left edge is wider than
+// cellGap. Whitespace is kept inside a cell but never starts one, so a cell's
+// left edge is the position of its first visible glyph.
+func splitCells(glyphs []pdf.Text) []textCell {
+	var cells []textCell
+	var text strings.Builder
+	var current textCell
+	open := false
+
+	closeCell := func() {
+		if open {
+			current.text = strings.TrimSpace(text.String())
+			cells = append(cells, current)
+		}
+		text.Reset()
+		open = false
+	}
+
+	for _, glyph := range glyphs {
+		blank := strings.TrimSpace(glyph.S) == ""
+		if open && glyph.X-current.right > cellGap {
+			closeCell()
+		}
+		if !open {
+			if blank {
+				continue
+			}
+			current = textCell{text: "", left: glyph.X, right: glyph.X}
+			open = true
+		}
+
+		text.WriteString(glyph.S)
+		if !blank {
+			current.right = max(current.right, glyph.X+glyph.W)
+		}
+	}
+	closeCell()
+
+	return cells
+}
+
+// tableColumn is a column of the rate table, located by its header cell.
+type tableColumn struct {
+	tenor  Tenor // empty for the CURRENCY column
+	center float64
+}
+
+// rateTable is the column layout of a rate sheet, read from its header line.
+type rateTable struct {
+	currency tableColumn
+	columns  []tableColumn
+}
+
+var errNoHeader = errors.New("could not find CURRENCY or READY headers")
+
+// findRateTable locates the header line (CURRENCY followed by the tenor
+// headers) and returns the table layout and the index of the header line.
+// CURRENCY on a line without READY, e.g. in a note above the table, is not the
+// header.
+func findRateTable(lines []textLine) (*rateTable, int, error) {
+	for i, line := range lines {
+		table := &rateTable{currency: tableColumn{tenor: "", center: 0}, columns: nil}
+		foundCurrency := false
+		for _, cell := range line.cells {
+			header := normalizeHeader(cell.text)
+			if header == "CURRENCY" {
+				table.currency.center = cell.center()
+				foundCurrency = true
+				continue
+			}
+			if tenor, ok := tenorHeaders[header]; ok {
+				table.columns = append(table.columns, tableColumn{tenor: tenor, center: cell.center()})
+			}
+		}
+
+		hasReady := slices.ContainsFunc(table.columns, func(c tableColumn) bool {
+			return c.tenor == TenorReady
+		})
+		if !foundCurrency || !hasReady {
+			continue
+		}
+
+		return table, i, nil
+	}
+
+	return nil, 0, errNoHeader
+}
+
+// tenors returns the tenors of the table's columns, left to right.
+func (t *rateTable) tenors() []Tenor {
+	tenors := make([]Tenor, 0, len(t.columns))
+	for _, column := range t.columns {
+		tenors = append(tenors, column.tenor)
+	}
+	return tenors
+}
+
+// column returns the column whose header is horizontally closest to the cell.
+// A nil column means the cell sits under CURRENCY.
+func (t *rateTable) column(cell textCell) *tableColumn {
+	best := &t.currency
+	bestDistance := math.Abs(cell.center() - t.currency.center)
+	for i := range t.columns {
+		if distance := math.Abs(cell.center() - t.columns[i].center); distance < bestDistance {
+			best = &t.columns[i]
+			bestDistance = distance
+		}
+	}
+
+	if best == &t.currency {
+		return nil
+	}
+	return best
+}
+
+// currencyCell returns the cell of a line that sits in the CURRENCY column.
+func (t *rateTable) currencyCell(line textLine) (textCell, bool) {
+	for _, cell := range line.cells {
+		if t.column(cell) == nil {
+			return cell, true
+		}
+	}
+	return textCell{text: "", left: 0, right: 0}, false
+}
+
+// normalizeHeader strips the padding SBP puts inside header cells (e.g.
+// " REA DY  ") so headers can be matched exactly.
+func normalizeHeader(text string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(text), ""))
+}
@@ -0,0 +1,131 @@
+package sbpfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	glyphWidth = 5.0
+	spaceWidth = 2.5
+)
+
+// text returns the glyphs of s set from x on a baseline at y, one per rune.
+func text(x, y float64, s string) []pdf.Text {
+	glyphs := make([]pdf.Text, 0, len(s))
+	for _, r := range s {
+		width := glyphWidth
+		if r == ' ' {
+			width = spaceWidth
+		}
+		glyphs = append(glyphs, pdf.Text{Font: "Helvetica", FontSize: 9, X: x, Y: y, W: width, S: string(r)})
+		x += width
+	}
+	return glyphs
+}
+
+// cellTexts returns the text of each cell of each line.
+func cellTexts(lines []textLine) [][]string {
+	texts := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		var cells []string
+		for _, cell := range line.cells {
+			cells = append(cells, cell.text)
+		}
+		texts = append(texts, cells)
+	}
+	return texts
+}
+
+// row returns a table line with a cell of each text centred on the matching x.
+func row(y float64, cells map[float64]string) textLine {
+	line := textLine{y: y, cells: nil}
+	for _, x := range []float64{50, 150, 250, 350} {
+		if s, ok := cells[x]; ok {
+			width := glyphWidth * float64(len(s))
+			line.cells = append(line.cells, textCell{text: s, left: x - width/2, right: x + width/2})
+		}
+	}
+	return line
+}
+
+func TestGroupLines(t *testing.T) {
+	// Glyphs arrive out of order, with baselines jittered within lineTolerance.
+	var glyphs []pdf.Text
+	glyphs = append(glyphs, text(100, 600.5, "281.8289")...)
+	glyphs = append(glyphs, text(10, 700, "CURRENCY")...)
+	glyphs = append(glyphs, text(10, 600, "USD")...)
+	glyphs = append(glyphs, text(100, 699, "READY")...)
+
+	assert.Equal(t, [][]string{{"CURRENCY", "READY"}, {"USD", "281.8289"}}, cellTexts(groupLines(glyphs)))
+}
+
+func TestSplitCells(t *testing.T) {
+	t.Run("padding inside a cell", func(t *testing.T) {
+		cells := splitCells(text(100, 700, " REA DY "))
+		assert.Equal(t, 1, len(cells))
+		assert.Equal(t, "REA DY", cells[0].text)
+		assert.Equal(t, 100+spaceWidth, cells[0].left) // the first visible glyph, not the padding
+		assert.Equal(t, "READY", normalizeHeader(cells[0].text))
+	})
+
+	t.Run("gap at cellGap", func(t *testing.T) {
+		// A gap of exactly cellGap stays inside the cell; any wider starts a new one.
+		glyphs := append(text(0, 700, "AB"), text(2*glyphWidth+cellGap, 700, "CD")...)
+		assert.Equal(t, []string{"ABCD"}, cellTexts([]textLine{{y: 700, cells: splitCells(glyphs)}})[0])
+
+		glyphs = append(text(0, 700, "AB"), text(2*glyphWidth+cellGap+0.01, 700, "CD")...)
+		assert.Equal(t, []string{"AB", "CD"}, cellTexts([]textLine{{y: 700, cells: splitCells(glyphs)}})[0])
+	})
+}
+
+func TestFindRateTable(t *testing.T) {
+	lines := []textLine{
+		row(750, map[float64]string{150: "27-Aug-2025"}),
+		// A decoy: CURRENCY and a tenor, but no READY.
+		row(725, map[float64]string{50: "CURRENCY", 250: "1-WEEK"}),
+		row(700, map[float64]string{50: "CURRENCY", 150: " REA DY ", 250: "1- WEEK"}),
+	}
+	table, index, err := findRateTable(lines)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, index)
+	assert.Equal(t, []Tenor{TenorReady, TenorOneWeek}, table.tenors())
+
+	// A header without READY is not the rate table.
+	lines[2] = row(700, map[float64]string{50: "CURRENCY", 250: "1-WEEK", 350: "1-MONTH"})
+	_, _, err = findRateTable(lines)
+	assert.IsError(t, err, errNoHeader)
+}
+
+func TestParseRateTableColumns(t *testing.T) {
+	date := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	lines := []textLine{
+		row(700, map[float64]string{50: "CURRENCY", 150: "READY", 250: "1-WEEK", 350: "1-MONTH"}),
+		row(680, map[float64]string{50: "USD", 150: "281.8289", 250: "282.0000", 350: "282.5000"}),
+		// A currency we don't model sits between two we do.
+		row(670, map[float64]string{50: "CNH", 150: "39.2500", 250: "39.3000", 350: "39.4000"}),
+		// EUR's 1-WEEK cell is missing; its 1-MONTH rate must stay in its column.
+		row(660, map[float64]string{50: "EUR", 150: "326.9215", 350: "327.9000"}),
+		// GBP follows the row with the missing cell.
+		row(650, map[float64]string{50: "GBP", 150: "378.1000", 250: "378.2000", 350: "378.3000"}),
+	}
+
+	sheet, err := parseRateTable(lines, date, "", discardLogger)
+	assert.NoError(t, err)
+
+	rates := sheet.Map()
+	assert.Equal(t, 3, len(sheet.Rates))
+	assert.Equal(t, [3]string{"281.8289", "282.0000", "282.5000"}, [3]string{rates[USD].Ready, rates[USD].OneWeek, rates[USD].OneMonth})
+	assert.Equal(t, [3]string{"326.9215", "", "327.9000"}, [3]string{rates[EUR].Ready, rates[EUR].OneWeek, rates[EUR].OneMonth})
+	assert.Equal(t, [3]string{"378.1000", "378.2000", "378.3000"}, [3]string{rates[GBP].Ready, rates[GBP].OneWeek, rates[GBP].OneMonth})
+
+	// A whole missing row leaves the rows around it untouched.
+	sheet, err = parseRateTable([]textLine{lines[0], lines[1], lines[4]}, date, "", discardLogger)
+	assert.NoError(t, err)
+	assert.Equal(t, "378.2000", sheet.Map()[GBP].OneWeek)
+	_, ok := sheet.Rate(EUR)
+	assert.False(t, ok)
+}
@@ -0,0 +1,24 @@
+package sbpfx
+
+import (
+	"log/slog"
+)
+
+// discardLogger is the logger of a client without WithLogger, and of
+// ParseRateSheet.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// WithLogger sets the logger the client records its decisions to. Defaults to
+// discarding them.
+//
+// At debug level the client logs each candidate URL it tries, migration
+// overrides it uses, cache hits and where the parser found the rate table's
+// header. At info level it logs soft 404s (a 200 response that wasn't a
+// rate-sheet PDF) and LatestAvailable falling back to an earlier sheet. At
+// warn level it logs failed requests, sheets whose header couldn't be found
+// and sheets with currency rows that yielded no rate.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
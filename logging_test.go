@@ -0,0 +1,110 @@
+package sbpfx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+)
+
+// logRecords returns a client that logs JSON at debug level, and a function
+// returning the records logged so far.
+func logRecords(t *testing.T, cassette string, options ...httpr.ClientOption) (*sbpfx.Client, func() []map[string]any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: false, Level: slog.LevelDebug, ReplaceAttr: nil}))
+	_, fixtures := newFixtureClient(t, cassette)
+	client := fixtures.client(append([]httpr.ClientOption{sbpfx.WithLogger(logger)}, options...)...)
+
+	return client, func() []map[string]any {
+		var records []map[string]any
+		for line := range strings.Lines(buf.String()) {
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+// messages returns the records' levels and messages.
+func messages(records []map[string]any) []string {
+	var msgs []string
+	for _, record := range records {
+		msgs = append(msgs, record["level"].(string)+" "+record["msg"].(string)) //nolint:errcheck // JSONHandler always writes both
+	}
+	return msgs
+}
+
+func TestLogging(t *testing.T) {
+	client, records := logRecords(t, "TestGetExchangeRatesDualFormat")
+
+	_, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+
+	logged := records()
+	assert.Equal(t, []string{
+		"DEBUG tried candidate URL",
+		"INFO soft 404: candidate URL answered 200 without a rate sheet",
+		"DEBUG tried candidate URL",
+		"DEBUG found rate table header",
+		"DEBUG skipped unknown currency",
+		"DEBUG skipped unknown currency",
+	}, messages(logged))
+
+	long, soft404, bare := logged[0], logged[1], logged[2]
+	assert.Equal(t, "long", long["scheme"])
+	assert.Equal(t, "not_found", long["outcome"])
+	assert.Equal(t, any(200.0), long["status"])
+	assert.Equal(t, long["url"], soft404["url"])
+	assert.Contains(t, soft404["content_type"].(string), "text/html") //nolint:errcheck // asserted by Contains
+	assert.NotZero(t, soft404["reason"])
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/17-Jul-26.pdf", bare["url"])
+	assert.Equal(t, "ok", bare["outcome"])
+	assert.Equal(t, bare["url"], logged[3]["url"])
+	assert.Equal(t, []any{"CNH", "KZT"}, []any{logged[4]["currency"], logged[5]["currency"]})
+}
+
+func TestLoggingOverride(t *testing.T) {
+	client, records := logRecords(t, "TestGetExchangeRates")
+
+	_, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-06-30"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	logged := records()
+	assert.Equal(t, []string{"DEBUG using migration override", "DEBUG tried candidate URL"}, messages(logged))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/30-Jun-26_1.pdf", logged[0]["url"])
+	assert.Equal(t, any(404.0), logged[1]["status"])
+}
+
+func TestLoggingRulesOverride(t *testing.T) {
+	// An override loaded from a rules file is logged like the built-in ones.
+	rules, err := sbpfx.ParseRules([]byte(rulesYAML + "  2026-07-17: [/17-Jul-26_1.pdf]\n"))
+	assert.NoError(t, err)
+	resolver, err := sbpfx.NewRulesResolver(rules)
+	assert.NoError(t, err)
+
+	client, records := logRecords(t, "TestGetExchangeRatesDualFormat", sbpfx.WithResolver(resolver))
+	_, err = client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	logged := records()
+	assert.Equal(t, []string{"DEBUG using migration override", "DEBUG tried candidate URL"}, messages(logged))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/17-Jul-26_1.pdf", logged[0]["url"])
+}
+
+func TestLoggingMalformedSheet(t *testing.T) {
+	client, records := logRecords(t, "TestGetExchangeRatesMalformedSheet")
+
+	_, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-06-01"))
+	assert.IsError(t, err, sbpfx.ErrMalformedSheet)
+
+	msgs := messages(records())
+	assert.Equal(t, "WARN rate table header not found", msgs[len(msgs)-1])
+}
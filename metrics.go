@@ -0,0 +1,218 @@
+package sbpfx
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "sbpfx"
+
+// Outcomes of a candidate request, as recorded in the outcome label.
+const (
+	outcomeOK          = "ok"           // A real rate-sheet PDF was downloaded
+	outcomeNotModified = "not_modified" // A cached PDF was revalidated
+	outcomeNotFound    = "not_found"    // No sheet at the path (non-200, or not a PDF)
+	outcomeCanceled    = "canceled"     // The request was cancelled, e.g. once a parallel probe found the sheet
+	outcomeError       = "error"        // The request or body read failed
+)
+
+// Naming schemes of candidate URLs, as recorded in the scheme label.
+const (
+	schemeLong     = "long"     // Prefix and DD-month-YYYY, the current era
+	schemePrefixed = "prefixed" // Prefix and DD-Mon-YY, the archive
+	schemeBare     = "bare"     // DD-Mon-YY, the June 2026 window and the current era's fallback
+	schemeOverride = "override" // A date's override in the resolver's rules
+	schemeCustom   = "custom"   // A URL off SBP's document host, from a caller's Resolver
+)
+
+// longDatePath matches the DD-month-YYYY names of the current era, e.g.
+// ...-14-july-2026.pdf.
+var longDatePath = regexp.MustCompile(`-\d{2}-[a-z]+-\d{4}\.pdf$`)
+
+// WithMetrics registers Prometheus metrics for the client's work with reg:
+//
+//	sbpfx_candidate_requests_total{scheme,outcome}  Requests per candidate URL
+//	sbpfx_download_duration_seconds{scheme}         Latency of candidate requests
+//	sbpfx_download_size_bytes                       Size of downloaded PDFs
+//	sbpfx_parse_duration_seconds                    Time spent parsing PDFs
+//	sbpfx_parse_failures_total                      PDFs that weren't parseable sheets
+//	sbpfx_cache_requests_total{cache,result}        Lookups in the pdf, sheet and store caches
+//	sbpfx_latest_sheet_timestamp_seconds            Business date of the latest sheet fetched
+//
+// scheme is the naming scheme of the candidate path: "long" (prefix and
+// DD-month-YYYY), "prefixed" (prefix and DD-Mon-YY), "bare" (DD-Mon-YY) or
+// "override" (a date's override in the resolver's rules, built in or loaded
+// from a file), or "custom" for a URL a caller's Resolver put off SBP's
+// document host. In the current era, requests
+// for "bare" paths show how often the dual-name fallback fires. outcome is
+// one of ok, not_modified, not_found, canceled or error, and result is hit or
+// miss.
+//
+// Clients sharing a registry share its metrics. WithMetrics panics if reg
+// already holds different metrics under these names.
+func WithMetrics(reg prometheus.Registerer) ClientOption {
+	return func(c *Client) {
+		c.metrics = newMetrics(reg)
+	}
+}
+
+// metrics holds the client's Prometheus collectors. A nil *metrics records
+// nothing, so a client without WithMetrics pays only for the nil checks.
+type metrics struct {
+	requests      *prometheus.CounterVec
+	downloadTime  *prometheus.HistogramVec
+	downloadSize  prometheus.Histogram
+	parseTime     prometheus.Histogram
+	parseFailures prometheus.Counter
+	cacheRequests *prometheus.CounterVec
+	latestSheet   prometheus.Gauge
+
+	mu     sync.Mutex
+	latest time.Time
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	return &metrics{
+		requests: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "candidate_requests_total",
+			Help:      "Requests for candidate rate-sheet URLs, by path naming scheme and outcome.",
+		}, []string{"scheme", "outcome"})),
+		downloadTime: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "download_duration_seconds",
+			Help:      "Latency of candidate rate-sheet requests, including reading the body.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"scheme"})),
+		downloadSize: register(reg, prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "download_size_bytes",
+			Help:      "Size of downloaded rate-sheet PDFs.",
+			Buckets:   prometheus.ExponentialBuckets(16<<10, 2, 9), //nolint:mnd // 16 KiB to 4 MiB
+		})),
+		parseTime: register(reg, prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "parse_duration_seconds",
+			Help:      "Time spent parsing rate-sheet PDFs.",
+			Buckets:   prometheus.DefBuckets,
+		})),
+		parseFailures: register(reg, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "parse_failures_total",
+			Help:      "Downloaded PDFs that were not parseable rate sheets.",
+		})),
+		cacheRequests: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cache_requests_total",
+			Help:      "Lookups in the client's PDF cache, sheet cache and store, by result.",
+		}, []string{"cache", "result"})),
+		latestSheet: register(reg, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "latest_sheet_timestamp_seconds",
+			Help:      "Business date (midnight UTC) of the most recent rate sheet fetched, as a Unix time.",
+		})),
+		mu:     sync.Mutex{},
+		latest: time.Time{},
+	}
+}
+
+// register registers collector with reg, or returns the equal collector reg
+// already holds.
+func register[T prometheus.Collector](reg prometheus.Registerer, collector T) T {
+	if err := reg.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+// candidate records one candidate request for a URL of the given scheme that
+// took elapsed. size is the PDF's size for an ok outcome.
+func (m *metrics) candidate(scheme, outcome string, elapsed time.Duration, size int) {
+	if m == nil {
+		return
+	}
+
+	m.requests.WithLabelValues(scheme, outcome).Inc()
+	m.downloadTime.WithLabelValues(scheme).Observe(elapsed.Seconds())
+	if outcome == outcomeOK {
+		m.downloadSize.Observe(float64(size))
+	}
+}
+
+// parsed records a parse that took elapsed and whether it failed.
+func (m *metrics) parsed(elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.parseTime.Observe(elapsed.Seconds())
+	if err != nil {
+		m.parseFailures.Inc()
+	}
+}
+
+// cacheLookup records a hit or miss in the named cache.
+func (m *metrics) cacheLookup(cache string, hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// fetched records that the sheet for date was fetched, moving the latest-sheet
+// gauge forward if it is the newest yet.
+func (m *metrics) fetched(date time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if date.After(m.latest) {
+		m.latest = date
+		m.latestSheet.Set(float64(date.Unix()))
+	}
+}
+
+// candidateScheme names the naming scheme of a candidate. An override is one
+// whatever its path looks like.
+func candidateScheme(cand candidate) string {
+	if cand.override {
+		return schemeOverride
+	}
+	return urlScheme(cand.url)
+}
+
+// urlScheme names the naming scheme a candidate URL's path follows.
+func urlScheme(url string) string {
+	path, ok := strings.CutPrefix(url, BaseURL)
+	if !ok {
+		return schemeCustom
+	}
+
+	switch {
+	case strings.HasPrefix(path, ratePrefix) && longDatePath.MatchString(path):
+		return schemeLong
+	case strings.HasPrefix(path, ratePrefix):
+		return schemePrefixed
+	default:
+		return schemeBare
+	}
+}
@@ -0,0 +1,100 @@
+package sbpfx_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// metric returns the first series of the named metric.
+func metric(t *testing.T, reg *prometheus.Registry, name string) *dto.Metric {
+	t.Helper()
+
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0]
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	cache, err := sbpfx.NewFSCache(t.TempDir())
+	assert.NoError(t, err)
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates", "TestGetExchangeRatesDualFormat", "TestGetExchangeRatesMalformedSheet")
+	client := fixtures.client(sbpfx.WithMetrics(reg), sbpfx.WithCache(cache), sbpfx.WithSheetCache(4))
+
+	for _, date := range []string{"2025-08-27", "2025-08-27", "2025-08-28", "2026-07-17", "2026-06-01"} {
+		_, _ = client.GetRateSheet(t.Context(), sbpfx.ForDate(date))
+	}
+
+	// 2026-07-17 is posted under its bare name, so the long name misses first.
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP sbpfx_candidate_requests_total Requests for candidate rate-sheet URLs, by path naming scheme and outcome.
+# TYPE sbpfx_candidate_requests_total counter
+sbpfx_candidate_requests_total{outcome="not_found",scheme="long"} 1
+sbpfx_candidate_requests_total{outcome="not_found",scheme="prefixed"} 1
+sbpfx_candidate_requests_total{outcome="ok",scheme="bare"} 2
+sbpfx_candidate_requests_total{outcome="ok",scheme="prefixed"} 1
+# HELP sbpfx_cache_requests_total Lookups in the client's PDF cache, sheet cache and store, by result.
+# TYPE sbpfx_cache_requests_total counter
+sbpfx_cache_requests_total{cache="pdf",result="miss"} 4
+sbpfx_cache_requests_total{cache="sheet",result="hit"} 1
+sbpfx_cache_requests_total{cache="sheet",result="miss"} 4
+# HELP sbpfx_parse_failures_total Downloaded PDFs that were not parseable rate sheets.
+# TYPE sbpfx_parse_failures_total counter
+sbpfx_parse_failures_total 1
+`), "sbpfx_candidate_requests_total", "sbpfx_cache_requests_total", "sbpfx_parse_failures_total"))
+
+	assert.Equal(t, uint64(3), metric(t, reg, "sbpfx_parse_duration_seconds").GetHistogram().GetSampleCount())
+	assert.Equal(t, uint64(3), metric(t, reg, "sbpfx_download_size_bytes").GetHistogram().GetSampleCount())
+
+	latest := time.Date(2026, 7, 17, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, float64(latest.Unix()), metric(t, reg, "sbpfx_latest_sheet_timestamp_seconds").GetGauge().GetValue())
+
+	// Another client can share the registry and its metrics.
+	_, err = fixtures.client(sbpfx.WithMetrics(reg)).GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), metric(t, reg, "sbpfx_parse_duration_seconds").GetHistogram().GetSampleCount())
+}
+
+func TestMetricsOverrideScheme(t *testing.T) {
+	// Overrides are labelled as such whether built in or loaded from a file,
+	// and whether fetched in turn or probed in parallel.
+	rules, err := sbpfx.ParseRules([]byte(rulesYAML + "  2026-07-17: [/17-Jul-26_1.pdf]\n"))
+	assert.NoError(t, err)
+	resolver, err := sbpfx.NewRulesResolver(rules)
+	assert.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+	_, fixtures := newFixtureClient(t)
+	for _, client := range []*sbpfx.Client{
+		fixtures.client(sbpfx.WithMetrics(reg)),
+		fixtures.client(sbpfx.WithMetrics(reg), sbpfx.WithResolver(resolver)),
+		fixtures.client(sbpfx.WithMetrics(reg), sbpfx.WithResolver(resolver), sbpfx.WithParallelProbe(sbpfx.ProbeGET)),
+	} {
+		_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+		assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	}
+	_, err = fixtures.client(sbpfx.WithMetrics(reg)).GetRateSheet(t.Context(), sbpfx.ForDate("2026-06-30"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP sbpfx_candidate_requests_total Requests for candidate rate-sheet URLs, by path naming scheme and outcome.
+# TYPE sbpfx_candidate_requests_total counter
+sbpfx_candidate_requests_total{outcome="not_found",scheme="bare"} 1
+sbpfx_candidate_requests_total{outcome="not_found",scheme="long"} 1
+sbpfx_candidate_requests_total{outcome="not_found",scheme="override"} 3
+`), "sbpfx_candidate_requests_total"))
+}
@@ -3,16 +3,71 @@ package sbpfx
 import (
 	"fmt"
 	"time"
+
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx/calendar"
 )
 
 const (
-	HoursInDay = 24 // Hours in a day for time truncation
+	HoursInDay     = 24            // Hours in a day for time truncation
+	pktUTCOffset   = 5 * time.Hour // Pakistan Standard Time is UTC+5 with no DST
+	defaultWorkers = 4             // Concurrent fetches for date ranges; kept low to be polite to SBP
+	sheetPrecision = 4             // Decimal places SBP quotes rates to
+	MaxPrecision   = 20            // Decimal places Precision allows at most
 )
 
+// pakistanTime is the zone SBP publishes on. Pakistan has not observed DST
+// since 2009, so a fixed UTC+5 zone is an exact fallback when the host has no
+// tz database.
+var pakistanTime = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Karachi"); err == nil {
+		return loc
+	}
+	return time.FixedZone("PKT", int(pktUTCOffset.Seconds()))
+}()
+
+// ClientOption configures a Client. ClientOptions implement httpr.ClientOption
+// so they can be passed to New alongside httpr's own options.
+type ClientOption func(*Client)
+
+// Client implements httpr.ClientOption. It leaves the HTTP client untouched;
+// New applies the option to the sbpfx Client instead.
+func (o ClientOption) Client(*httpr.Client) {}
+
+// WithLocation sets the time zone that decides which business date "today" is
+// and that ForTime converts to before truncating. Defaults to Asia/Karachi,
+// the calendar SBP publishes on, which a nil loc also selects.
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		if loc == nil {
+			loc = pakistanTime
+		}
+		c.location = loc
+	}
+}
+
+// WithCalendar sets the business-day calendar used when searching for sheets,
+// e.g. by LatestAvailable, so that weekends and holidays are skipped without a
+// request. Defaults to calendar.Pakistan(); pass calendar.New() to try every
+// day. An explicitly requested date is always fetched, whatever the calendar
+// says.
+func WithCalendar(cal *calendar.Calendar) ClientOption {
+	return func(c *Client) {
+		c.calendar = cal
+	}
+}
+
 type Option func(*option) error
 
+// option holds per-request settings. Dates are business dates, represented as
+// midnight UTC of the calendar day in the client's location.
 type option struct {
-	date time.Time
+	date      time.Time
+	location  *time.Location
+	lookback  int
+	workers   int
+	rounding  RoundingMode
+	precision int32
 }
 
 // ForDate sets a specific date for the exchange rate request using a string in YYYY-MM-DD format.
@@ -29,15 +84,80 @@ func ForDate(dateStr string) Option {
 }
 
 // ForTime sets a specific date for the exchange rate request using a time.Time.
+// The time is converted to the client's location (Asia/Karachi by default)
+// before being truncated to a day, so 2025-08-26T20:00Z requests 2025-08-27.
 func ForTime(date time.Time) Option {
 	return func(c *option) error {
-		c.date = date.UTC().Truncate(HoursInDay * time.Hour)
+		c.date = businessDate(date, c.location)
+		return nil
+	}
+}
+
+// LatestAvailable makes the request fall back to the most recent published
+// sheet when the requested date has none (weekends, holidays, or today before
+// SBP has posted). Up to maxLookback earlier days are tried, newest first;
+// dates with no sheet or a malformed one are skipped. The served date is
+// reported in RateSheet.Date and ExchangeRate.Date.
+func LatestAvailable(maxLookback int) Option {
+	return func(c *option) error {
+		if maxLookback < 0 {
+			return fmt.Errorf("invalid lookback %d, must not be negative", maxLookback)
+		}
+
+		c.lookback = maxLookback
+		return nil
+	}
+}
+
+// Workers sets how many dates GetExchangeRatesRange fetches concurrently.
+// Defaults to 4.
+func Workers(n int) Option {
+	return func(c *option) error {
+		if n < 1 {
+			return fmt.Errorf("invalid worker count %d, must be at least 1", n)
+		}
+
+		c.workers = n
+		return nil
+	}
+}
+
+// Rounding sets how Convert and RateSheet.Cross round their results. Defaults
+// to RoundHalfEven.
+func Rounding(mode RoundingMode) Option {
+	return func(c *option) error {
+		c.rounding = mode
+		return nil
+	}
+}
+
+// Precision sets how many decimal places Convert and RateSheet.Cross round
+// their results to, at most MaxPrecision. Defaults to 4, the precision SBP quotes rates
+// to.
+func Precision(places int) Option {
+	return func(c *option) error {
+		if places < 0 || places > MaxPrecision {
+			return fmt.Errorf("invalid precision %d, must be between 0 and %d", places, MaxPrecision)
+		}
+
+		c.precision = int32(places)
 		return nil
 	}
 }
 
-func defaultConfig() *option {
+func defaultConfig(loc *time.Location) *option {
 	return &option{
-		date: time.Now().UTC().Truncate(HoursInDay * time.Hour),
+		date:      businessDate(time.Now(), loc),
+		location:  loc,
+		lookback:  0,
+		workers:   defaultWorkers,
+		rounding:  RoundHalfEven,
+		precision: sheetPrecision,
 	}
 }
+
+// businessDate returns the calendar day t falls on in loc, as midnight UTC.
+func businessDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
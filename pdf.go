@@ -2,138 +2,230 @@ package sbpfx
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"strconv"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/ledongthuc/pdf"
 )
 
-// parsePDFContent extracts text from PDF content and parses exchange rates.
-func parsePDFContent(content []byte, date time.Time, url string) (map[Currency]*ExchangeRate, error) {
+const currencyCodeLen = 3 // ISO 4217 codes are three letters
+
+var errNoRates = errors.New("no exchange rates found in PDF")
+
+// printedDateLayouts are the formats SBP uses for the date printed above the
+// table, e.g. 27-Aug-25.
+var printedDateLayouts = []string{"2-Jan-06", "2-January-2006"}
+
+// ParseRateSheet parses a rate sheet PDF, e.g. one saved by DownloadRateSheet
+// or FetchRateSheet. date and url are recorded on the sheet and its rows, and
+// SHA256 is computed from content; FetchedAt is left zero. A PDF that isn't a
+// parseable rate sheet returns an error wrapping ErrMalformedSheet.
+func ParseRateSheet(content []byte, date time.Time, url string) (*RateSheet, error) {
+	return parseSheet(content, date, url, discardLogger)
+}
+
+// parseSheet implements ParseRateSheet, logging the parser's decisions to
+// logger.
+func parseSheet(content []byte, date time.Time, url string, logger *slog.Logger) (*RateSheet, error) {
+	sheet, err := parsePDFContent(content, date, url, logger)
+	if err != nil {
+		// SBP posted a few malformed/unrelated PDFs during the June 2026
+		// migration (e.g. 2026-06-01, 03, 04, 05); surface a clear, date-tagged
+		// error rather than the raw parser message so callers can distinguish
+		// it from a bug.
+		return nil, fmt.Errorf("no valid rate sheet for %s (%s): %w: %w", date.Format("2006-01-02"), url, ErrMalformedSheet, err)
+	}
+
+	sum := sha256.Sum256(content)
+	sheet.SHA256 = hex.EncodeToString(sum[:])
+
+	return sheet, nil
+}
+
+// parsePDFContent reconstructs the rate table from the glyph positions in the
+// PDF and parses it into a RateSheet. Fetch metadata (SHA256, FetchedAt) is
+// left for the caller to fill in.
+func parsePDFContent(content []byte, date time.Time, url string, logger *slog.Logger) (*RateSheet, error) {
 	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PDF reader: %w", err)
 	}
 
-	var fullText strings.Builder
+	var lines []textLine
 	for i := 1; i <= reader.NumPage(); i++ {
 		page := reader.Page(i)
 		if page.V.IsNull() {
 			continue
 		}
 
-		text, err := page.GetPlainText(nil)
+		pageLines, err := extractLines(page)
 		if err != nil {
 			continue
 		}
-		fullText.WriteString(text)
+		lines = append(lines, pageLines...)
 	}
 
-	return parseExchangeRateText(fullText.String(), date, url)
+	return parseRateTable(lines, date, url, logger)
 }
 
-// parseExchangeRateText parses extracted text to find exchange rates.
-func parseExchangeRateText(text string, date time.Time, url string) (map[Currency]*ExchangeRate, error) {
-	rates := make(map[Currency]*ExchangeRate)
-
-	lines := strings.Split(text, "\n")
-
-	// Find the CURRENCY header line
-	currencyLineIndex := -1
-	readyLineIndex := -1
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "CURRENCY" {
-			currencyLineIndex = i
-		}
-		if line == "READY" {
-			readyLineIndex = i
-		}
-		if currencyLineIndex != -1 && readyLineIndex != -1 {
-			break
-		}
+// parseRateTable parses the lines of a rate sheet into a RateSheet.
+//
+// Each line below the header whose CURRENCY cell holds a currency code is a
+// row, and every number on that line is assigned to the tenor column whose
+// header sits closest to it horizontally. A rate is therefore tied to its own
+// currency row and column: a missing cell leaves that tenor empty instead of
+// shifting the rest of the sheet. Other lines below the table are kept as
+// notes.
+//
+// Currency rows that yield no rate are logged to logger, since they usually
+// mean the layout has shifted under the parser.
+func parseRateTable(lines []textLine, date time.Time, url string, logger *slog.Logger) (*RateSheet, error) {
+	table, headerIndex, err := findRateTable(lines)
+	if err != nil {
+		logger.Warn("rate table header not found", slog.Int("lines", len(lines)), slog.Any("error", err))
+		return nil, err
 	}
-
-	if currencyLineIndex == -1 || readyLineIndex == -1 {
-		return nil, errors.New("could not find CURRENCY or READY headers")
+	logger.Debug("found rate table header", slog.Int("line", headerIndex), slog.Int("lines", len(lines)), slog.Any("tenors", table.tenors()))
+
+	sheet := &RateSheet{
+		RequestedDate: date,
+		Date:          date,
+		PrintedDate:   findPrintedDate(lines[:headerIndex]),
+		URL:           url,
+		SHA256:        "",
+		FetchedAt:     time.Time{},
+		ParserVersion: ParserVersion,
+		Rates:         nil,
+		Notes:         nil,
 	}
 
-	// Parse currencies and rates
-	currencies := []string{}
-	readyRates := []string{}
+	var unknown, unquoted []string
+	for _, line := range lines[headerIndex+1:] {
+		cell, ok := table.currencyCell(line)
+		if !ok || !isCurrencyCode(cell.text) {
+			if note := noteText(line); note != "" {
+				sheet.Notes = append(sheet.Notes, note)
+			}
+			continue
+		}
 
-	// Collect currencies starting after the CURRENCY header
-	for i := currencyLineIndex + 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" || line == "READY" {
+		if !Currency(cell.text).IsValid() {
+			// A currency we don't model (e.g. CNH).
+			logger.Debug("skipped unknown currency", slog.String("currency", cell.text))
+			unknown = append(unknown, cell.text)
 			continue
 		}
-		// Stop when we hit notes or other sections
-		if strings.Contains(strings.ToUpper(line), "EXCHANGE RATES FOR MARK") {
-			break
+
+		row := &ExchangeRate{
+			Currency:   Currency(cell.text),
+			Date:       date,
+			URL:        url,
+			Ready:      "",
+			OneWeek:    "",
+			TwoWeek:    "",
+			OneMonth:   "",
+			TwoMonth:   "",
+			ThreeMonth: "",
+			FourMonth:  "",
+			FiveMonth:  "",
+			SixMonth:   "",
+			NineMonth:  "",
+			OneYear:    "",
 		}
-		// Check if it's a 3-letter currency code
-		if len(line) == 3 && Currency(line).IsValid() {
-			currencies = append(currencies, line)
+		for _, cell := range line.cells {
+			column := table.column(cell)
+			if column == nil {
+				continue
+			}
+
+			// SBP prints 0.0000 for tenors it doesn't quote; leave those empty.
+			rate, err := ParseRate(cell.text)
+			if err != nil || rate.Sign() <= 0 {
+				continue
+			}
+
+			if field := row.tenorField(column.tenor); *field == "" {
+				*field = cell.text
+			}
 		}
-	}
 
-	// Collect READY rates starting after the READY header
-	for i := readyLineIndex + 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
+		// A row without a spot rate is not a usable quote.
+		if row.Ready == "" {
+			unquoted = append(unquoted, cell.text)
 			continue
 		}
-		// Stop when we hit notes or other sections
-		if strings.Contains(strings.ToUpper(line), "EXCHANGE RATES FOR MARK") {
-			break
-		}
-		// Check if it's a number (rate)
-		if rate, err := strconv.ParseFloat(line, 64); err == nil && rate > 0 {
-			readyRates = append(readyRates, line)
-		}
+		sheet.Rates = append(sheet.Rates, row)
+	}
+
+	if len(unquoted) > 0 {
+		logger.Warn("currency rows without a ready rate",
+			slog.Int("currency_rows", len(sheet.Rates)+len(unknown)+len(unquoted)),
+			slog.Int("rates", len(sheet.Rates)),
+			slog.Any("currencies", unquoted),
+		)
 	}
 
-	// Match currencies with their ready rates
-	minLen := minValue(len(currencies), len(readyRates))
-	for i := range minLen {
-		// Validate that it's a valid rate string before storing
-		if _, err := strconv.ParseFloat(readyRates[i], 64); err == nil {
-			currency := Currency(currencies[i])
-			rates[currency] = &ExchangeRate{
-				Currency:   currency,
-				Ready:      readyRates[i], // Store as string
-				Date:       date,
-				URL:        url,
-				OneWeek:    "",
-				TwoWeek:    "",
-				OneMonth:   "",
-				TwoMonth:   "",
-				ThreeMonth: "",
-				FourMonth:  "",
-				FiveMonth:  "",
-				SixMonth:   "",
-				NineMonth:  "",
-				OneYear:    "",
+	if len(sheet.Rates) == 0 {
+		return nil, errNoRates
+	}
+
+	return sheet, nil
+}
+
+// findPrintedDate returns the date SBP prints above the table, or the zero
+// time if none of the lines carry one.
+func findPrintedDate(lines []textLine) time.Time {
+	for _, line := range lines {
+		for _, cell := range line.cells {
+			for _, layout := range printedDateLayouts {
+				if printed, err := time.Parse(layout, cell.text); err == nil {
+					return printed
+				}
 			}
 		}
 	}
+	return time.Time{}
+}
 
-	if len(rates) == 0 {
-		return nil, errors.New("no exchange rates found in PDF")
+// noteText joins the cells of a non-row line into a note. Lines holding only
+// a number are footnote markers and yield "".
+func noteText(line textLine) string {
+	texts := make([]string, 0, len(line.cells))
+	for _, cell := range line.cells {
+		texts = append(texts, cell.text)
 	}
+	text := strings.Join(texts, " ")
 
-	return rates, nil
+	if _, err := ParseRate(text); err == nil {
+		return ""
+	}
+	return text
 }
 
-// minValue returns the minimum of two integers.
-func minValue(a, b int) int {
-	if a < b {
-		return a
+// isCurrencyCode reports whether text looks like a three-letter ISO currency
+// code, whether or not it is one we model.
+func isCurrencyCode(text string) bool {
+	if len(text) != currencyCodeLen {
+		return false
+	}
+	for _, r := range text {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
 	}
-	return b
+	return true
 }
+
+// tenorHeaders maps the column headers printed on the sheet to their tenor.
+var tenorHeaders = func() map[string]Tenor {
+	headers := make(map[string]Tenor)
+	for _, tenor := range Tenors() {
+		headers[string(tenor)] = tenor
+	}
+	return headers
+}()
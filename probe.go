@@ -0,0 +1,170 @@
+package sbpfx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mistermoe/httpr"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const probeBytes = 1024 // Bytes a ranged probe asks for; enough to see the %PDF signature
+
+// Probe is how a client requests a date's candidate URLs in parallel. See
+// WithParallelProbe.
+type Probe int
+
+const (
+	// ProbeGET downloads every candidate at once.
+	ProbeGET Probe = iota + 1
+
+	// ProbeHEAD sends each candidate a HEAD request, then downloads the first
+	// whose status and Content-Type look like a rate sheet. A server that
+	// doesn't support HEAD leaves the download to decide.
+	ProbeHEAD
+
+	// ProbeRange requests the first kilobyte of each candidate, then downloads
+	// the first that starts like a rate-sheet PDF. A server that ignores the
+	// Range header sends the whole body, of which only the first kilobyte is
+	// read.
+	ProbeRange
+)
+
+// WithParallelProbe requests all of a date's candidate URLs at once instead of
+// one after another, so a soft 404 on the first candidate doesn't double the
+// latency of a lookup. The first candidate in priority order that yields a
+// real rate sheet wins, and the requests for the rest are cancelled.
+//
+// ProbeGET is fastest but downloads up to one PDF per candidate. ProbeHEAD and
+// ProbeRange cost a small extra request per candidate but download only the
+// winner. Dates with a single candidate are always fetched directly.
+func WithParallelProbe(probe Probe) ClientOption {
+	return func(c *Client) {
+		c.probe = probe
+	}
+}
+
+// probeRateSheet fetches the first of several candidate URLs, in priority
+// order, that yields a real rate-sheet PDF, requesting them in parallel.
+func (c *Client) probeRateSheet(ctx context.Context, date time.Time, candidates []candidate) (*CacheEntry, error) {
+	// Cancels the requests still in flight once a winner is found.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		entry *CacheEntry
+		err   *CandidateError
+	}
+	results := make([]chan result, len(candidates))
+	for i, cand := range candidates {
+		results[i] = make(chan result, 1)
+		go func() {
+			if c.probe == ProbeGET {
+				entry, err := c.fetchPDF(ctx, date, cand, nil)
+				results[i] <- result{entry: entry, err: err}
+				return
+			}
+			results[i] <- result{entry: nil, err: c.probePDF(ctx, date, cand)}
+		}()
+	}
+
+	lookupErr := &LookupError{Date: date, URLs: nil, Failures: nil}
+	for i, cand := range candidates {
+		res := <-results[i]
+		if res.err == nil && res.entry == nil {
+			// The probe passed; download the sheet.
+			res.entry, res.err = c.fetchPDF(ctx, date, cand, nil)
+		}
+		if res.err != nil {
+			lookupErr.URLs = append(lookupErr.URLs, res.err.URL)
+			lookupErr.Failures = append(lookupErr.Failures, res.err)
+			continue
+		}
+
+		return res.entry, nil
+	}
+
+	return nil, lookupErr
+}
+
+// probePDF checks whether a candidate URL looks like it serves a rate sheet
+// with a HEAD or ranged GET request, as c.probe says. It returns nil if the
+// candidate is worth downloading.
+func (c *Client) probePDF(ctx context.Context, date time.Time, cand candidate) *CandidateError {
+	candidateURL := cand.url
+	method, opts := http.MethodHead, []httpr.RequestOption(nil)
+	if c.probe == ProbeRange {
+		method, opts = http.MethodGet, []httpr.RequestOption{httpr.Header("Range", fmt.Sprintf("bytes=0-%d", probeBytes-1))}
+	}
+
+	ctx, span := c.tracer.Start(ctx, "sbpfx.probePDF", trace.WithAttributes(
+		attrDate.String(date.Format("2006-01-02")),
+		attrURL.String(candidateURL),
+		attrScheme.String(candidateScheme(cand)),
+		attrMethod.String(method),
+	))
+	defer span.End()
+
+	record := func(outcome string, status int) {
+		span.SetAttributes(attrOutcome.String(outcome), attrStatusCode.Int(status))
+		c.logger.DebugContext(ctx, "probed candidate URL",
+			slog.String("date", date.Format("2006-01-02")),
+			slog.String("url", candidateURL),
+			slog.String("method", method),
+			slog.Int("status", status),
+			slog.String("outcome", outcome),
+		)
+	}
+
+	resp, err := c.httpClient.SendRequest(ctx, method, candidateURL, opts...)
+	if err != nil {
+		outcome := outcomeError
+		if ctx.Err() != nil {
+			outcome = outcomeCanceled
+		}
+		record(outcome, 0)
+		err = fmt.Errorf("failed to probe PDF: %w", err)
+		recordError(span, err)
+		return &CandidateError{URL: candidateURL, StatusCode: 0, Err: err}
+	}
+	defer resp.Body.Close()
+
+	notFound := func(reason string) *CandidateError {
+		record(outcomeNotFound, resp.StatusCode)
+		return &CandidateError{
+			URL:        candidateURL,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%w: %s for URL: %s", ErrSheetNotFound, reason, candidateURL),
+		}
+	}
+
+	switch {
+	case method == http.MethodHead && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented):
+		// HEAD isn't supported; let the download decide.
+	case resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent:
+		return notFound(fmt.Sprintf("status %d", resp.StatusCode))
+	case method == http.MethodHead:
+		// There's no body to check, so a soft 404 shows only in its Content-Type.
+		if ok, reason := looksLikePDF(resp.Header.Get("Content-Type"), []byte(pdfSignature)); !ok {
+			return notFound(reason)
+		}
+	default:
+		head, err := io.ReadAll(io.LimitReader(resp.Body, probeBytes))
+		if err != nil {
+			record(outcomeError, resp.StatusCode)
+			err = fmt.Errorf("failed to probe PDF: %w", err)
+			recordError(span, err)
+			return &CandidateError{URL: candidateURL, StatusCode: resp.StatusCode, Err: err}
+		}
+		if ok, reason := looksLikePDF(resp.Header.Get("Content-Type"), head); !ok {
+			return notFound(reason)
+		}
+	}
+
+	record(outcomeOK, resp.StatusCode)
+	return nil
+}
@@ -0,0 +1,79 @@
+package sbpfx_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/vcr"
+)
+
+func TestParallelProbe(t *testing.T) {
+	const (
+		long = "/assets/document/mark-to-market-revaluation-exchange-rate-17-july-2026.pdf"
+		bare = "/assets/document/17-Jul-26.pdf"
+	)
+
+	tests := []struct {
+		probe sbpfx.Probe
+		want  []string // Paths requested, sorted
+	}{
+		{sbpfx.ProbeGET, []string{bare, long}},
+		{sbpfx.ProbeHEAD, []string{bare, bare, long}},
+		{sbpfx.ProbeRange, []string{bare, bare, long}},
+	}
+	for _, tt := range tests {
+		_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+		client := fixtures.client(sbpfx.WithParallelProbe(tt.probe))
+
+		// The long name is a soft 404 however it's probed.
+		sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+		assert.NoError(t, err)
+		assert.Equal(t, bareJul17, sheet.URL)
+
+		requests := fixtures.Requests()
+		slices.Sort(requests)
+		assert.Equal(t, tt.want, requests)
+
+		_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-18"))
+		assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	}
+}
+
+func TestParallelProbePriority(t *testing.T) {
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+
+	// The second candidate never answers until its request is cancelled.
+	cancelled := make(chan struct{})
+	transport := vcr.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == "slow.example" {
+			<-r.Context().Done()
+			close(cancelled)
+			return nil, r.Context().Err()
+		}
+		return fixtures.Transport().RoundTrip(r)
+	})
+
+	// Both candidates serve the sheet; the first in priority order wins.
+	mirror := "https://mirror.example/assets/document/17-Jul-26.pdf"
+	resolver := staticResolver(mirror, "https://slow.example/17-Jul-26.pdf", bareJul17)
+	client := sbpfx.New(
+		httpr.HTTPClient(http.Client{Transport: transport}),
+		sbpfx.WithResolver(resolver),
+		sbpfx.WithParallelProbe(sbpfx.ProbeGET),
+	)
+
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	assert.Equal(t, mirror, sheet.URL)
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the losing request was not cancelled")
+	}
+}
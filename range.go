@@ -0,0 +1,131 @@
+package sbpfx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+)
+
+// RangeResult is the outcome of fetching one business date of a range. Exactly
+// one of Rates and Err is set.
+type RangeResult struct {
+	Date  time.Time                  `json:"date"`            // Business date requested
+	Rates map[Currency]*ExchangeRate `json:"rates,omitempty"` // Rates keyed by currency
+	Err   error                      `json:"-"`               // Why the date failed
+}
+
+// GetExchangeRatesRange fetches the rate sheets for every business day from
+// from through to, inclusive, using up to Workers(n) concurrent requests.
+// Weekends and holidays in the client's calendar are skipped without a request.
+//
+// A failure for one date does not abort the range: it is reported in that
+// date's RangeResult.Err and the other dates are still fetched. The returned
+// error is only set if the options or the range itself are invalid. Results
+// are ordered by date. ForDate and ForTime are ignored; LatestAvailable applies
+// to each date.
+func (c *Client) GetExchangeRatesRange(ctx context.Context, from, to time.Time, opts ...Option) ([]RangeResult, error) {
+	cfg, err := c.config(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	from = businessDate(from, c.location)
+	to = businessDate(to, c.location)
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: %s is before %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	dates := c.calendar.BusinessDaysBetween(from, to)
+	results := make([]RangeResult, len(dates))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(cfg.workers, len(dates)) {
+		wg.Go(func() {
+			for i := range jobs {
+				results[i] = c.fetchRangeDate(ctx, dates[i], cfg.lookback)
+			}
+		})
+	}
+
+	for i := range dates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+// fetchRangeDate fetches a single date of a range. Once ctx is done the
+// remaining dates fail fast with its error instead of issuing requests.
+func (c *Client) fetchRangeDate(ctx context.Context, date time.Time, lookback int) RangeResult {
+	result := RangeResult{Date: date, Rates: nil, Err: nil}
+	if err := ctx.Err(); err != nil {
+		result.Err = fmt.Errorf("rate sheet for %s: %w", date.Format("2006-01-02"), err)
+		return result
+	}
+
+	sheet, err := c.rateSheet(ctx, date, lookback)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Rates = sheet.Map()
+	return result
+}
+
+// Rates returns an iterator over a currency's rates for every business day from
+// from through to, inclusive, in date order. Sheets are fetched lazily, one at
+// a time as the loop advances, so breaking out of the loop stops fetching.
+//
+// A date that fails yields a nil rate and its error, and iteration continues
+// with the next date; errors.Is(err, ErrSheetNotFound) identifies days SBP did
+// not publish on. Invalid options or a done ctx yield a single error and end
+// the iteration. ForDate and ForTime are ignored; LatestAvailable applies to
+// each date.
+//
+//	for rate, err := range client.Rates(ctx, from, to, sbpfx.USD) {
+//		if err != nil {
+//			continue
+//		}
+//		fmt.Println(rate.Date, rate.Ready)
+//	}
+func (c *Client) Rates(ctx context.Context, from, to time.Time, currency Currency, opts ...Option) iter.Seq2[*ExchangeRate, error] {
+	return func(yield func(*ExchangeRate, error) bool) {
+		cfg, err := c.config(opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		// Iterators may be ranged over more than once, so leave from and to
+		// untouched.
+		start, end := businessDate(from, c.location), businessDate(to, c.location)
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			if !c.calendar.IsBusinessDay(date) {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				yield(nil, fmt.Errorf("rate sheet for %s: %w", date.Format("2006-01-02"), err))
+				return
+			}
+
+			sheet, err := c.rateSheet(ctx, date, cfg.lookback)
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+
+			if !yield(sheetRate(sheet, currency)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package sbpfx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const decimalBase = 10
+
+// RoundingMode selects how a Rate is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // To nearest, ties to even ("banker's rounding")
+	RoundHalfUp                       // To nearest, ties away from zero
+	RoundHalfDown                     // To nearest, ties toward zero
+	RoundDown                         // Toward zero (truncate)
+	RoundUp                           // Away from zero
+	RoundFloor                        // Toward negative infinity
+	RoundCeiling                      // Toward positive infinity
+)
+
+// roundingModeNames are the names String and ParseRoundingMode use, indexed by
+// mode.
+var roundingModeNames = []string{"half-even", "half-up", "half-down", "down", "up", "floor", "ceiling"}
+
+// String returns the mode's name, e.g. "half-even".
+func (m RoundingMode) String() string {
+	if m < 0 || int(m) >= len(roundingModeNames) {
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+	return roundingModeNames[m]
+}
+
+// ParseRoundingMode returns the mode with the given name, as returned by
+// String: half-even, half-up, half-down, down, up, floor or ceiling.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	for mode, modeName := range roundingModeNames {
+		if name == modeName {
+			return RoundingMode(mode), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rounding mode %q, expected one of: %s", name, strings.Join(roundingModeNames, ", "))
+}
+
+// Rate is an exact decimal number, such as a rate read off the sheet. It keeps
+// the number of decimal places it was written with, so "281.8289" formats back
+// as "281.8289" and "0.50" as "0.50", and arithmetic on it is exact.
+//
+// The zero value is 0. Rates are immutable; arithmetic returns a new Rate. Use
+// Cmp or Equal rather than == to compare them.
+type Rate struct {
+	coef  *big.Int // value is coef / 10^scale; nil means 0
+	scale int32    // digits after the decimal point
+}
+
+// NewRate returns coef / 10^scale, e.g. NewRate(2818289, 4) is 281.8289. A
+// negative scale multiplies, so NewRate(5, -3) is 5000.
+func NewRate(coef int64, scale int32) Rate {
+	if scale < 0 {
+		return Rate{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale)), scale: 0}
+	}
+	return Rate{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseRate parses a plain decimal such as "281.8289", "-0.5" or "1000". The
+// scale is the number of digits after the point. Exponents, thousands
+// separators and non-finite values are rejected.
+func ParseRate(s string) (Rate, error) {
+	digits, negative := strings.CutPrefix(s, "-")
+	if !negative {
+		digits = strings.TrimPrefix(s, "+")
+	}
+
+	whole, frac, hasPoint := strings.Cut(digits, ".")
+	if (whole == "" && frac == "") || (hasPoint && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+
+	coef, ok := new(big.Int).SetString(whole+frac, decimalBase)
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	if negative {
+		coef.Neg(coef)
+	}
+
+	return Rate{coef: coef, scale: int32(len(frac))}, nil //nolint:gosec // scale is bounded by the input length
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Scale returns the number of digits after the decimal point.
+func (r Rate) Scale() int32 {
+	return r.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of r.
+func (r Rate) Sign() int {
+	return r.coefficient().Sign()
+}
+
+// IsZero reports whether r is 0, at any scale.
+func (r Rate) IsZero() bool {
+	return r.Sign() == 0
+}
+
+// Cmp compares r and other numerically, returning -1, 0 or +1. Scale is
+// ignored, so 1.5 and 1.50 compare equal.
+func (r Rate) Cmp(other Rate) int {
+	a, b := align(r, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether r and other are numerically equal.
+func (r Rate) Equal(other Rate) bool {
+	return r.Cmp(other) == 0
+}
+
+// Add returns r + other, at the larger of the two scales.
+func (r Rate) Add(other Rate) Rate {
+	a, b := align(r, other)
+	return Rate{coef: a.Add(a, b), scale: max(r.scale, other.scale)}
+}
+
+// Sub returns r - other, at the larger of the two scales.
+func (r Rate) Sub(other Rate) Rate {
+	a, b := align(r, other)
+	return Rate{coef: a.Sub(a, b), scale: max(r.scale, other.scale)}
+}
+
+// Mul returns r × other exactly, at the sum of the two scales.
+func (r Rate) Mul(other Rate) Rate {
+	coef := new(big.Int).Mul(r.coefficient(), other.coefficient())
+	return Rate{coef: coef, scale: r.scale + other.scale}
+}
+
+// Neg returns -r.
+func (r Rate) Neg() Rate {
+	return Rate{coef: new(big.Int).Neg(r.coefficient()), scale: r.scale}
+}
+
+// Float64 returns the nearest float64 to r, for analytics that don't need
+// exact values.
+func (r Rate) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(r.coefficient(), pow10(r.scale)).Float64()
+	return f
+}
+
+// String formats r with exactly Scale digits after the decimal point.
+func (r Rate) String() string {
+	coef := r.coefficient()
+	digits := new(big.Int).Abs(coef).String()
+
+	sign := ""
+	if coef.Sign() < 0 {
+		sign = "-"
+	}
+	if r.scale == 0 {
+		return sign + digits
+	}
+
+	scale := int(r.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// Round returns r rounded to scale digits after the decimal point using mode.
+// Rounding to a larger scale pads with zeros. A negative scale is treated as 0.
+func (r Rate) Round(scale int32, mode RoundingMode) Rate {
+	scale = max(scale, 0)
+	if scale >= r.scale {
+		return Rate{coef: new(big.Int).Mul(r.coefficient(), pow10(scale-r.scale)), scale: scale}
+	}
+
+	return Rate{coef: roundQuo(r.coefficient(), pow10(r.scale-scale), mode), scale: scale}
+}
+
+// Div returns r / other rounded to scale digits after the decimal point using
+// mode. A negative scale is treated as 0. Like big.Int, it panics if other is
+// zero.
+func (r Rate) Div(other Rate, scale int32, mode RoundingMode) Rate {
+	scale = max(scale, 0)
+	// r/other = (a / 10^as) / (b / 10^bs), so the result's coefficient at
+	// scale is a * 10^(bs+scale) / (b * 10^as).
+	num := new(big.Int).Mul(r.coefficient(), pow10(other.scale+scale))
+	den := new(big.Int).Mul(other.coefficient(), pow10(r.scale))
+
+	return Rate{coef: roundQuo(num, den, mode), scale: scale}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Rate) UnmarshalText(text []byte) error {
+	parsed, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// MarshalJSON encodes r as a JSON string, e.g. "281.8289", so that consumers
+// that decode numbers as floats don't lose precision.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a JSON string or a JSON number.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("failed to decode rate: %w", err)
+		}
+		data = []byte(s)
+	}
+
+	return r.UnmarshalText(data)
+}
+
+func (r Rate) coefficient() *big.Int {
+	if r.coef == nil {
+		return new(big.Int)
+	}
+	return r.coef
+}
+
+// align returns fresh copies of the coefficients of a and b scaled to the
+// larger of their scales.
+func align(a, b Rate) (*big.Int, *big.Int) {
+	scale := max(a.scale, b.scale)
+	x := new(big.Int).Mul(a.coefficient(), pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.coefficient(), pow10(scale-b.scale))
+	return x, y
+}
+
+// roundQuo returns num / den rounded to an integer using mode.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// quo is truncated toward zero; away is the step that moves it away from
+	// zero, and half compares the discarded remainder with one half.
+	away := big.NewInt(int64(num.Sign() * den.Sign()))
+	half := new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(new(big.Int).Abs(den))
+
+	var roundAway bool
+	switch mode {
+	case RoundDown:
+		roundAway = false
+	case RoundUp:
+		roundAway = true
+	case RoundFloor:
+		roundAway = away.Sign() < 0
+	case RoundCeiling:
+		roundAway = away.Sign() > 0
+	case RoundHalfUp:
+		roundAway = half >= 0
+	case RoundHalfDown:
+		roundAway = half > 0
+	case RoundHalfEven:
+		roundAway = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	}
+
+	if roundAway {
+		quo.Add(quo, away)
+	}
+	return quo
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(decimalBase), big.NewInt(int64(n)), nil)
+}
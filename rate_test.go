@@ -0,0 +1,148 @@
+package sbpfx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+)
+
+func mustRate(t *testing.T, s string) sbpfx.Rate {
+	t.Helper()
+
+	rate, err := sbpfx.ParseRate(s)
+	assert.NoError(t, err)
+	return rate
+}
+
+func TestParseRate(t *testing.T) {
+	valid := map[string]string{
+		"281.8289": "281.8289",
+		"0.50":     "0.50",
+		"-0.5":     "-0.5",
+		"+12":      "12",
+		".25":      "0.25",
+		"1000":     "1000",
+		"0.0000":   "0.0000",
+	}
+	for input, want := range valid {
+		assert.Equal(t, want, mustRate(t, input).String(), "ParseRate(%q)", input)
+	}
+
+	for _, input := range []string{"", "-", ".", "1.", "1e5", "NaN", "Inf", "1,000.00", "--1", "-+1", "12.3.4", " 1"} {
+		_, err := sbpfx.ParseRate(input)
+		assert.IsError(t, err, sbpfx.ErrInvalidRate, "ParseRate(%q)", input)
+	}
+
+	assert.Equal(t, "281.8289", sbpfx.NewRate(2818289, 4).String())
+	assert.Equal(t, "5000", sbpfx.NewRate(5, -3).String())
+	assert.Equal(t, "0", sbpfx.Rate{}.String())
+}
+
+func TestRateArithmetic(t *testing.T) {
+	usd := mustRate(t, "281.8289")
+
+	assert.Equal(t, "282.3289", usd.Add(mustRate(t, "0.5")).String())
+	assert.Equal(t, "0.2503", mustRate(t, "282.0792").Sub(usd).String())
+	assert.Equal(t, "281828.90000000", usd.Mul(mustRate(t, "1000.0000")).String())
+	assert.Equal(t, "-281.8289", usd.Neg().String())
+
+	// 0.1 + 0.2 is exactly 0.3, unlike with float64.
+	assert.True(t, mustRate(t, "0.1").Add(mustRate(t, "0.2")).Equal(mustRate(t, "0.3")))
+
+	assert.Equal(t, 0, mustRate(t, "1.5").Cmp(mustRate(t, "1.50")))
+	assert.Equal(t, -1, mustRate(t, "1.4999").Cmp(mustRate(t, "1.5")))
+	assert.Equal(t, 1, usd.Cmp(sbpfx.Rate{}))
+	assert.True(t, sbpfx.Rate{}.IsZero())
+	assert.Equal(t, 281.8289, usd.Float64())
+}
+
+func TestRateRounding(t *testing.T) {
+	tests := []struct {
+		value string
+		mode  sbpfx.RoundingMode
+		want  string
+	}{
+		{"2.5", sbpfx.RoundHalfEven, "2"},
+		{"3.5", sbpfx.RoundHalfEven, "4"},
+		{"-2.5", sbpfx.RoundHalfEven, "-2"},
+		{"2.5", sbpfx.RoundHalfUp, "3"},
+		{"-2.5", sbpfx.RoundHalfUp, "-3"},
+		{"2.5", sbpfx.RoundHalfDown, "2"},
+		{"2.51", sbpfx.RoundHalfDown, "3"},
+		{"2.9", sbpfx.RoundDown, "2"},
+		{"-2.9", sbpfx.RoundDown, "-2"},
+		{"2.1", sbpfx.RoundUp, "3"},
+		{"-2.1", sbpfx.RoundUp, "-3"},
+		{"-2.1", sbpfx.RoundFloor, "-3"},
+		{"2.9", sbpfx.RoundFloor, "2"},
+		{"-2.9", sbpfx.RoundCeiling, "-2"},
+		{"2.1", sbpfx.RoundCeiling, "3"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, mustRate(t, tt.value).Round(0, tt.mode).String(), "Round(%s, %d)", tt.value, tt.mode)
+	}
+
+	assert.Equal(t, "1.5000", mustRate(t, "1.5").Round(4, sbpfx.RoundHalfEven).String())
+	assert.Equal(t, "0.3333", mustRate(t, "1").Div(mustRate(t, "3"), 4, sbpfx.RoundHalfEven).String())
+	assert.Equal(t, "0.6667", mustRate(t, "2").Div(mustRate(t, "3"), 4, sbpfx.RoundHalfEven).String())
+	assert.Equal(t, "-0.6666", mustRate(t, "2").Div(mustRate(t, "-3"), 4, sbpfx.RoundDown).String())
+
+	for _, tt := range tests {
+		mode, err := sbpfx.ParseRoundingMode(tt.mode.String())
+		assert.NoError(t, err)
+		assert.Equal(t, tt.mode, mode)
+	}
+	assert.Equal(t, "half-even", sbpfx.RoundHalfEven.String())
+	_, err := sbpfx.ParseRoundingMode("nearest")
+	assert.Error(t, err)
+}
+
+func TestRateJSON(t *testing.T) {
+	type quote struct {
+		Rate sbpfx.Rate `json:"rate"`
+	}
+
+	data, err := json.Marshal(quote{Rate: mustRate(t, "281.8290")})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"rate":"281.8290"}`, string(data))
+
+	var decoded quote
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "281.8290", decoded.Rate.String())
+
+	// Bare JSON numbers are accepted too.
+	assert.NoError(t, json.Unmarshal([]byte(`{"rate":326.9215}`), &decoded))
+	assert.Equal(t, "326.9215", decoded.Rate.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"rate":"abc"}`), &decoded))
+}
+
+func TestExchangeRateTenorRates(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	rate, ok := sheet.Rate(sbpfx.USD)
+	assert.True(t, ok)
+
+	ready, ok := rate.ReadyRate()
+	assert.True(t, ok)
+	assert.Equal(t, "281.8289", ready.String())
+
+	oneYear, ok := rate.OneYearRate()
+	assert.True(t, ok)
+	assert.Equal(t, "294.2690", oneYear.String())
+
+	nineMonth, ok := rate.TenorRate(sbpfx.TenorNineMonth)
+	assert.True(t, ok)
+	assert.Equal(t, "291.4649", nineMonth.String())
+
+	// Tenors SBP doesn't quote are reported as missing.
+	bdt, ok := sheet.Rate(sbpfx.BDT)
+	assert.True(t, ok)
+	_, ok = bdt.OneWeekRate()
+	assert.False(t, ok)
+}
@@ -0,0 +1,142 @@
+package sbpfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Resolver maps a business date to the URLs its rate sheet may be published
+// at, in priority order. The client requests each in turn until one serves a
+// real rate-sheet PDF.
+//
+// SBP renames its sheets every so often. A Resolver lets callers follow a new
+// naming scheme, or pin a one-off irregular name, without waiting for a
+// release: chain their own resolver ahead of DefaultResolver with
+// ChainResolvers.
+type Resolver interface {
+	Resolve(ctx context.Context, date time.Time) ([]string, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(ctx context.Context, date time.Time) ([]string, error)
+
+// Resolve calls f.
+func (f ResolverFunc) Resolve(ctx context.Context, date time.Time) ([]string, error) {
+	return f(ctx, date)
+}
+
+// DefaultResolver returns the resolver for the naming schemes SBP has used so
+// far, including the irregular names of the June 2026 migration (see
+// DefaultRules). It is the client's resolver unless WithResolver sets another.
+func DefaultResolver() Resolver {
+	r := &RulesResolver{path: "", rules: atomic.Pointer[compiledRules]{}, mu: sync.Mutex{}, modTime: time.Time{}}
+	r.rules.Store(defaultRules)
+	return r
+}
+
+// ChainResolvers returns a Resolver yielding the candidates of each resolver
+// in turn, dropping any URL an earlier resolver already yielded. A resolver
+// that fails is skipped as long as another yields candidates; if none do, the
+// chain returns their errors.
+func ChainResolvers(resolvers ...Resolver) Resolver {
+	return chainResolver(resolvers)
+}
+
+type chainResolver []Resolver
+
+// Resolve implements Resolver.
+func (resolvers chainResolver) Resolve(ctx context.Context, date time.Time) ([]string, error) {
+	candidates, err := resolvers.resolveCandidates(ctx, date)
+	return candidateURLs(candidates), err
+}
+
+func (resolvers chainResolver) resolveCandidates(ctx context.Context, date time.Time) ([]candidate, error) {
+	var candidates []candidate
+	var errs []error
+	seen := map[string]bool{}
+	for _, resolver := range resolvers {
+		resolved, err := resolveCandidates(ctx, resolver, date)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, cand := range resolved {
+			if !seen[cand.url] {
+				seen[cand.url] = true
+				candidates = append(candidates, cand)
+			}
+		}
+	}
+
+	if len(candidates) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return candidates, nil
+}
+
+// WithResolver sets the Resolver the client finds each date's candidate URLs
+// with. Defaults to DefaultResolver.
+func WithResolver(resolver Resolver) ClientOption {
+	return func(c *Client) {
+		c.resolver = resolver
+	}
+}
+
+// candidate is a URL a date's sheet may be published at. override marks a URL
+// taken from a date's override in a resolver's rules, rather than from a
+// naming scheme.
+type candidate struct {
+	url      string
+	override bool
+}
+
+// candidateResolver is implemented by the resolvers that know which of their
+// URLs are overrides: those following Rules, and chains of them.
+type candidateResolver interface {
+	resolveCandidates(ctx context.Context, date time.Time) ([]candidate, error)
+}
+
+// resolveCandidates returns a resolver's candidates for date. Only a
+// candidateResolver can mark overrides; any other resolver's URLs are taken
+// as they come.
+func resolveCandidates(ctx context.Context, resolver Resolver, date time.Time) ([]candidate, error) {
+	if r, ok := resolver.(candidateResolver); ok {
+		return r.resolveCandidates(ctx, date)
+	}
+
+	urls, err := resolver.Resolve(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, 0, len(urls))
+	for _, url := range urls {
+		candidates = append(candidates, candidate{url: url, override: false})
+	}
+	return candidates, nil
+}
+
+// candidateURLs returns the candidates' URLs.
+func candidateURLs(candidates []candidate) []string {
+	urls := make([]string, 0, len(candidates))
+	for _, cand := range candidates {
+		urls = append(urls, cand.url)
+	}
+	return urls
+}
+
+// candidates returns the client resolver's candidates for date. A date with
+// no candidates has no sheet.
+func (c *Client) candidates(ctx context.Context, date time.Time) ([]candidate, error) {
+	candidates, err := resolveCandidates(ctx, c.resolver, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve rate sheet URLs for %s: %w", date.Format("2006-01-02"), err)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no candidate URLs for %s", ErrSheetNotFound, date.Format("2006-01-02"))
+	}
+	return candidates, nil
+}
@@ -0,0 +1,95 @@
+package sbpfx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+)
+
+const bareJul17 = "https://www.sbp.org.pk/assets/document/17-Jul-26.pdf"
+
+// staticResolver yields the same candidates for every date.
+func staticResolver(urls ...string) sbpfx.Resolver {
+	return sbpfx.ResolverFunc(func(context.Context, time.Time) ([]string, error) {
+		return urls, nil
+	})
+}
+
+func TestDefaultResolver(t *testing.T) {
+	date := time.Date(2026, time.July, 17, 0, 0, 0, 0, time.UTC)
+
+	urls, err := sbpfx.DefaultResolver().Resolve(t.Context(), date)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-17-july-2026.pdf",
+		bareJul17,
+	}, urls)
+}
+
+func TestWithResolver(t *testing.T) {
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+	client := fixtures.client(sbpfx.WithResolver(staticResolver(bareJul17)))
+
+	assert.Equal(t, bareJul17, client.GetUrl(sbpfx.ForDate("2026-07-17")))
+
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	assert.Equal(t, bareJul17, sheet.URL)
+	assert.Equal(t, []string{"/assets/document/17-Jul-26.pdf"}, fixtures.Requests())
+
+	// A resolver with nothing to offer means there is no sheet.
+	client = fixtures.client(sbpfx.WithResolver(staticResolver()))
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	assert.Equal(t, "", client.GetUrl(sbpfx.ForDate("2026-07-17")))
+	assert.Equal(t, 0, len(fixtures.Requests()))
+}
+
+func TestGetUrlContext(t *testing.T) {
+	client := sbpfx.New(sbpfx.WithResolver(staticResolver(bareJul17)))
+	url, err := client.GetUrlContext(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	assert.Equal(t, bareJul17, url)
+
+	_, err = sbpfx.New(sbpfx.WithResolver(staticResolver())).GetUrlContext(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	// A resolver waiting on the network gives up with the caller's context.
+	blocked := sbpfx.ResolverFunc(func(ctx context.Context, _ time.Time) ([]string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err = sbpfx.New(sbpfx.WithResolver(blocked)).GetUrlContext(ctx, sbpfx.ForDate("2026-07-17"))
+	assert.IsError(t, err, context.DeadlineExceeded)
+}
+
+func TestChainResolvers(t *testing.T) {
+	errDown := errors.New("index unavailable")
+	failing := sbpfx.ResolverFunc(func(context.Context, time.Time) ([]string, error) {
+		return nil, errDown
+	})
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+	client := fixtures.client(sbpfx.WithResolver(sbpfx.ChainResolvers(
+		failing,
+		staticResolver("https://mirror.example/17-Jul-26.pdf", bareJul17),
+		sbpfx.DefaultResolver(),
+	)))
+
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+	assert.Equal(t, bareJul17, sheet.URL)
+	// The mirror 404s and the default resolver's bare URL isn't tried twice.
+	assert.Equal(t, []string{"/17-Jul-26.pdf", "/assets/document/17-Jul-26.pdf"}, fixtures.Requests())
+
+	// The chain fails only if no resolver yields a candidate.
+	client = fixtures.client(sbpfx.WithResolver(sbpfx.ChainResolvers(failing, staticResolver())))
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.IsError(t, err, errDown)
+}
@@ -0,0 +1,427 @@
+package sbpfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules describe where SBP publishes each date's rate sheet: naming eras, each
+// with the URL templates in use over a date range, and per-date overrides for
+// sheets uploaded under irregular names. They can be written as YAML or JSON:
+//
+//	eras:
+//	  - name: bare
+//	    from: 2026-06-01
+//	    to: 2026-07-02
+//	    templates: ["/{dd}-{Mon}-{yy}.pdf"]
+//	overrides:
+//	  2026-06-30: ["/30-Jun-26_1.pdf"]
+//
+// A template is a path under BaseURL, or an absolute http(s) URL, with these
+// placeholders for the date:
+//
+//	{d} {dd}           day, e.g. 7 or 07
+//	{mm}               month number, e.g. 07
+//	{Mon} {mon} {MON}  short month, e.g. Jul, jul or JUL
+//	{Month} {month}    full month, e.g. July or july
+//	{yy} {yyyy}        year, e.g. 26 or 2026
+//
+// Override URLs are used as written. Dates are YYYY-MM-DD.
+type Rules struct {
+	Eras      []Era               `json:"eras"                yaml:"eras"`
+	Overrides map[string][]string `json:"overrides,omitempty" yaml:"overrides,omitempty"` // Candidate URLs keyed by date
+}
+
+// Era is a date range over which SBP named its sheets the same way.
+type Era struct {
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty"`
+	From      string   `json:"from,omitempty" yaml:"from,omitempty"` // First date, inclusive; empty for no lower bound
+	To        string   `json:"to,omitempty"   yaml:"to,omitempty"`   // Last date, inclusive; empty for no upper bound
+	Templates []string `json:"templates"      yaml:"templates"`      // Candidate URL templates, in priority order
+}
+
+// migrationOverrides holds the transition-window sheets that SBP uploaded with
+// irregular names that fit none of the date-based schemes. Keyed by YYYY-MM-DD.
+var migrationOverrides = map[string]string{
+	"2026-06-30": "/30-Jun-26_1.pdf",            // bare date with a _1 suffix
+	"2026-07-02": ratePrefix + "-02-Jul-26.pdf", // prefix but legacy date style
+}
+
+// DefaultRules returns the built-in rules, which DefaultResolver follows.
+//
+// SBP has hosted the daily sheets under several naming schemes as it migrated
+// to the /assets/document host:
+//   - through 2026-05-31: prefix + DD-Mon-YY (e.g. 27-Aug-25)
+//   - 2026-06-01 to 2026-07-02: bare DD-Mon-YY (e.g. 23-Jun-26)
+//   - from 2026-07-03: prefix + DD-month-YYYY (e.g. 14-july-2026) AND the
+//     bare DD-Mon-YY name (e.g. 17-Jul-26) — see below
+//   - migrationOverrides: transition-window sheets with irregular names
+//
+// From July 2026 onward SBP posts the same daily sheet under either the long
+// prefixed name or the bare DD-Mon-YY name with no discernible pattern (e.g.
+// .../mark-to-market-revaluation-exchange-rate-14-july-2026.pdf on one day and
+// .../17-Jul-26.pdf on another), so the current era yields both candidates and
+// the client tries each until one resolves to a real PDF.
+func DefaultRules() *Rules {
+	overrides := map[string][]string{}
+	for date, path := range migrationOverrides {
+		overrides[date] = []string{path}
+	}
+
+	return &Rules{
+		Eras: []Era{
+			{Name: "archive", From: "", To: "2026-05-31", Templates: []string{ratePrefix + "-{dd}-{Mon}-{yy}.pdf"}},
+			{Name: "bare", From: "2026-06-01", To: "2026-07-02", Templates: []string{"/{dd}-{Mon}-{yy}.pdf"}},
+			{Name: "current", From: "2026-07-03", To: "", Templates: []string{
+				ratePrefix + "-{dd}-{month}-{yyyy}.pdf",
+				"/{dd}-{Mon}-{yy}.pdf",
+			}},
+		},
+		Overrides: overrides,
+	}
+}
+
+// defaultRules are DefaultRules compiled once; they are known to be valid.
+var defaultRules = func() *compiledRules {
+	compiled, err := compileRules(DefaultRules())
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}()
+
+// ParseRules parses and validates rules written as YAML or JSON. Unknown
+// fields are rejected, so a typo doesn't silently drop a rule.
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadRules reads and validates a YAML or JSON rules file.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules %s: %w", path, err)
+	}
+
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Validate checks that every date and template parses and that no two eras
+// overlap. The error wraps ErrInvalidRules and lists every problem found.
+func (r *Rules) Validate() error {
+	_, err := compileRules(r)
+	return err
+}
+
+// RulesResolver is a Resolver that follows Rules. Its rules can be replaced
+// while clients use it, e.g. by Reload when SBP uploads a sheet under a new
+// irregular name. It is safe for concurrent use.
+type RulesResolver struct {
+	path  string
+	rules atomic.Pointer[compiledRules]
+
+	mu      sync.Mutex // serializes reloads
+	modTime time.Time  // of the rules file when last loaded
+}
+
+// NewRulesResolver returns a resolver following rules, which must be valid.
+func NewRulesResolver(rules *Rules) (*RulesResolver, error) {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RulesResolver{path: "", rules: atomic.Pointer[compiledRules]{}, mu: sync.Mutex{}, modTime: time.Time{}}
+	r.rules.Store(compiled)
+	return r, nil
+}
+
+// LoadRulesResolver returns a resolver following the rules file at path,
+// which Reload and Watch read again.
+func LoadRulesResolver(path string) (*RulesResolver, error) {
+	r := &RulesResolver{path: path, rules: atomic.Pointer[compiledRules]{}, mu: sync.Mutex{}, modTime: time.Time{}}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Resolve implements Resolver.
+func (r *RulesResolver) Resolve(_ context.Context, date time.Time) ([]string, error) {
+	return candidateURLs(r.rules.Load().resolve(date)), nil
+}
+
+func (r *RulesResolver) resolveCandidates(_ context.Context, date time.Time) ([]candidate, error) {
+	return r.rules.Load().resolve(date), nil
+}
+
+// Rules returns the rules the resolver follows. The caller must not modify
+// them.
+func (r *RulesResolver) Rules() *Rules {
+	return r.rules.Load().rules
+}
+
+// Update replaces the resolver's rules. Invalid rules are rejected and the
+// current ones kept.
+func (r *RulesResolver) Update(rules *Rules) error {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+
+	r.rules.Store(compiled)
+	return nil
+}
+
+// Reload reads the resolver's rules file again. A file that fails to read or
+// validate is rejected and the current rules kept.
+func (r *RulesResolver) Reload() error {
+	if r.path == "" {
+		return errors.New("rules resolver has no rules file to reload")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read rules %s: %w", r.path, err)
+	}
+	rules, err := LoadRules(r.path)
+	if err != nil {
+		return err
+	}
+	if err := r.Update(rules); err != nil {
+		return fmt.Errorf("rules %s: %w", r.path, err)
+	}
+
+	r.modTime = info.ModTime()
+	return nil
+}
+
+// Watch reloads the rules file whenever its modification time changes,
+// checking every interval, until ctx is done. A change that fails to load is
+// passed to onError, if set, and the current rules are kept.
+func (r *RulesResolver) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// changed reports whether the rules file has changed since it was last
+// loaded. A file that can't be read counts as changed, so Reload reports why.
+func (r *RulesResolver) changed() bool {
+	info, err := os.Stat(r.path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return err != nil || !info.ModTime().Equal(r.modTime)
+}
+
+// compiledRules are Rules with their dates parsed and eras sorted.
+type compiledRules struct {
+	rules     *Rules
+	eras      []compiledEra
+	overrides map[string][]string
+}
+
+type compiledEra struct {
+	name      string
+	from, to  time.Time // zero for an open bound
+	templates []string
+}
+
+// templatePlaceholder matches a {placeholder} in a template.
+var templatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// templateFields expands each template placeholder for a date.
+var templateFields = map[string]func(time.Time) string{
+	"{d}":     func(t time.Time) string { return strconv.Itoa(t.Day()) },
+	"{dd}":    func(t time.Time) string { return t.Format("02") },
+	"{mm}":    func(t time.Time) string { return t.Format("01") },
+	"{Mon}":   func(t time.Time) string { return t.Format("Jan") },
+	"{mon}":   func(t time.Time) string { return strings.ToLower(t.Format("Jan")) },
+	"{MON}":   func(t time.Time) string { return strings.ToUpper(t.Format("Jan")) },
+	"{Month}": func(t time.Time) string { return t.Format("January") },
+	"{month}": func(t time.Time) string { return strings.ToLower(t.Format("January")) },
+	"{yy}":    func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()%YearModulo) },
+	"{yyyy}":  func(t time.Time) string { return strconv.Itoa(t.Year()) },
+}
+
+// compileRules validates rules and prepares them for resolving, collecting
+// every problem rather than stopping at the first.
+func compileRules(rules *Rules) (*compiledRules, error) {
+	var problems []string
+	compiled := &compiledRules{rules: rules, eras: nil, overrides: map[string][]string{}}
+
+	for i, era := range rules.Eras {
+		name := era.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i+1)
+		}
+
+		c := compiledEra{name: name, from: time.Time{}, to: time.Time{}, templates: era.Templates}
+		var err error
+		if era.From != "" {
+			if c.from, err = time.Parse("2006-01-02", era.From); err != nil {
+				problems = append(problems, fmt.Sprintf("era %s: invalid from date %q", name, era.From))
+			}
+		}
+		if era.To != "" {
+			if c.to, err = time.Parse("2006-01-02", era.To); err != nil {
+				problems = append(problems, fmt.Sprintf("era %s: invalid to date %q", name, era.To))
+			}
+		}
+		if !c.from.IsZero() && !c.to.IsZero() && c.to.Before(c.from) {
+			problems = append(problems, fmt.Sprintf("era %s: ends before it starts", name))
+		}
+		if len(era.Templates) == 0 {
+			problems = append(problems, fmt.Sprintf("era %s: no templates", name))
+		}
+		for _, template := range era.Templates {
+			if problem := checkTemplate(template); problem != "" {
+				problems = append(problems, fmt.Sprintf("era %s: template %q %s", name, template, problem))
+			}
+		}
+		compiled.eras = append(compiled.eras, c)
+	}
+
+	// An open lower bound sorts first, so consecutive eras overlap exactly
+	// when the earlier one doesn't end before the later one starts.
+	slices.SortStableFunc(compiled.eras, func(a, b compiledEra) int {
+		return a.from.Compare(b.from)
+	})
+	for i := 1; i < len(compiled.eras); i++ {
+		prev, next := compiled.eras[i-1], compiled.eras[i]
+		if prev.to.IsZero() || !prev.to.Before(next.from) {
+			problems = append(problems, fmt.Sprintf("eras %s and %s overlap", prev.name, next.name))
+		}
+	}
+
+	for date, urls := range rules.Overrides {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			problems = append(problems, fmt.Sprintf("override: invalid date %q", date))
+		}
+		if len(urls) == 0 {
+			problems = append(problems, fmt.Sprintf("override %s: no URLs", date))
+		}
+		for _, url := range urls {
+			if !isCandidateURL(url) {
+				problems = append(problems, fmt.Sprintf("override %s: %q is not a path or http(s) URL", date, url))
+			}
+		}
+		compiled.overrides[date] = absoluteURLs(urls)
+	}
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRules, strings.Join(problems, "; "))
+	}
+	return compiled, nil
+}
+
+// checkTemplate returns what is wrong with a template, or "" if nothing is.
+func checkTemplate(template string) string {
+	if !isCandidateURL(template) {
+		return "is not a path or http(s) URL"
+	}
+	for _, placeholder := range templatePlaceholder.FindAllString(template, -1) {
+		if _, ok := templateFields[placeholder]; !ok {
+			return "has unknown placeholder " + placeholder
+		}
+	}
+	if strings.ContainsAny(templatePlaceholder.ReplaceAllString(template, ""), "{}") {
+		return "has an unbalanced brace"
+	}
+	return ""
+}
+
+// isCandidateURL reports whether s is a path under BaseURL or an absolute
+// http(s) URL.
+func isCandidateURL(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// absoluteURLs resolves paths against BaseURL.
+func absoluteURLs(urls []string) []string {
+	absolute := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if strings.HasPrefix(url, "/") {
+			url = BaseURL + url
+		}
+		absolute = append(absolute, url)
+	}
+	return absolute
+}
+
+// resolve returns the candidates for a date: its override if it has one,
+// otherwise its era's templates expanded. A date no era covers has none.
+func (r *compiledRules) resolve(date time.Time) []candidate {
+	if urls, ok := r.overrides[date.Format("2006-01-02")]; ok {
+		candidates := make([]candidate, 0, len(urls))
+		for _, url := range urls {
+			candidates = append(candidates, candidate{url: url, override: true})
+		}
+		return candidates
+	}
+
+	for _, era := range r.eras {
+		if (era.from.IsZero() || !date.Before(era.from)) && (era.to.IsZero() || !date.After(era.to)) {
+			urls := make([]string, 0, len(era.templates))
+			for _, template := range era.templates {
+				urls = append(urls, templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+					return templateFields[placeholder](date)
+				}))
+			}
+
+			candidates := make([]candidate, 0, len(urls))
+			for _, url := range absoluteURLs(urls) {
+				candidates = append(candidates, candidate{url: url, override: false})
+			}
+			return candidates
+		}
+	}
+
+	return nil
+}
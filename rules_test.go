@@ -0,0 +1,151 @@
+package sbpfx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+)
+
+const rulesYAML = `
+eras:
+  - name: old
+    to: 2026-06-30
+    templates: ["/{dd}-{Mon}-{yy}.pdf"]
+  - name: new
+    from: 2026-07-01
+    templates:
+      - /rates/{yyyy}/{mm}/{d}-{month}.pdf
+      - https://mirror.example/{MON}{yy}/{dd}.pdf
+overrides:
+  2026-07-06: [/06-Jul-26_1.pdf]
+`
+
+const rulesJSON = `{
+  "eras": [
+    {"name": "old", "to": "2026-06-30", "templates": ["/{dd}-{Mon}-{yy}.pdf"]},
+    {"name": "new", "from": "2026-07-01", "templates": ["/rates/{yyyy}/{mm}/{d}-{month}.pdf", "https://mirror.example/{MON}{yy}/{dd}.pdf"]}
+  ],
+  "overrides": {"2026-07-06": ["/06-Jul-26_1.pdf"]}
+}`
+
+func resolve(t *testing.T, resolver sbpfx.Resolver, date string) []string {
+	t.Helper()
+
+	day, err := time.Parse("2006-01-02", date)
+	assert.NoError(t, err)
+	urls, err := resolver.Resolve(t.Context(), day)
+	assert.NoError(t, err)
+	return urls
+}
+
+func TestParseRules(t *testing.T) {
+	const base = "https://www.sbp.org.pk/assets/document"
+
+	for name, data := range map[string]string{"yaml": rulesYAML, "json": rulesJSON} {
+		t.Run(name, func(t *testing.T) {
+			rules, err := sbpfx.ParseRules([]byte(data))
+			assert.NoError(t, err)
+			resolver, err := sbpfx.NewRulesResolver(rules)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []string{base + "/30-Jun-26.pdf"}, resolve(t, resolver, "2026-06-30"))
+			assert.Equal(t, []string{
+				base + "/rates/2026/07/3-july.pdf",
+				"https://mirror.example/JUL26/03.pdf",
+			}, resolve(t, resolver, "2026-07-03"))
+			assert.Equal(t, []string{base + "/06-Jul-26_1.pdf"}, resolve(t, resolver, "2026-07-06"))
+		})
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	resolver, err := sbpfx.NewRulesResolver(sbpfx.DefaultRules())
+	assert.NoError(t, err)
+
+	for _, date := range []string{"2025-08-27", "2026-05-31", "2026-06-01", "2026-06-30", "2026-07-02", "2026-07-03", "2026-10-16"} {
+		assert.Equal(t, resolve(t, sbpfx.DefaultResolver(), date), resolve(t, resolver, date), date)
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  string
+	}{
+		{"overlapping eras", `
+eras:
+  - {name: a, to: 2026-07-01, templates: [/a.pdf]}
+  - {name: b, from: 2026-07-01, templates: [/b.pdf]}`, "eras a and b overlap"},
+		{"two open-ended eras", `
+eras:
+  - {name: a, from: 2026-01-01, templates: [/a.pdf]}
+  - {name: b, from: 2026-07-01, templates: [/b.pdf]}`, "eras a and b overlap"},
+		{"backwards era", `
+eras:
+  - {name: a, from: 2026-07-01, to: 2026-06-01, templates: [/a.pdf]}`, "era a: ends before it starts"},
+		{"bad date", `
+eras:
+  - {name: a, from: 01/07/2026, templates: [/a.pdf]}`, `era a: invalid from date "01/07/2026"`},
+		{"unknown placeholder", `
+eras:
+  - {name: a, templates: ["/{day}.pdf"]}`, "has unknown placeholder {day}"},
+		{"relative template", `
+eras:
+  - {name: a, templates: [a.pdf]}`, "is not a path or http(s) URL"},
+		{"empty override", `
+overrides:
+  2026-07-06: []`, "override 2026-07-06: no URLs"},
+		{"unknown field", `
+eras:
+  - {name: a, templates: [/a.pdf], until: 2026-07-01}`, "field until not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sbpfx.ParseRules([]byte(tt.rules))
+			assert.IsError(t, err, sbpfx.ErrInvalidRules)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestRulesResolverReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(rulesYAML), 0o600))
+
+	resolver, err := sbpfx.LoadRulesResolver(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resolve(t, resolver, "2026-07-07")))
+	watched := make(chan error, 1)
+	go resolver.Watch(t.Context(), time.Millisecond, func(err error) {
+		select {
+		case watched <- err:
+		default:
+		}
+	})
+
+	// An invalid file is reported and the current rules kept.
+	assert.NoError(t, os.WriteFile(path, []byte("eras: [{templates: [x]}]"), 0o600))
+	touch(t, path, time.Minute)
+	assert.IsError(t, <-watched, sbpfx.ErrInvalidRules)
+	assert.Equal(t, 2, len(resolve(t, resolver, "2026-07-07")))
+
+	// A valid change is picked up.
+	assert.NoError(t, os.WriteFile(path, []byte(rulesYAML+"  2026-07-07: [/a.pdf]\n"), 0o600))
+	touch(t, path, 2*time.Minute)
+	assert.NoError(t, resolver.Reload())
+	assert.Equal(t, []string{"https://www.sbp.org.pk/assets/document/a.pdf"}, resolve(t, resolver, "2026-07-07"))
+}
+
+// touch moves a file's modification time forward, so a rewrite within the
+// file system's timestamp resolution still counts as a change.
+func touch(t *testing.T, path string, by time.Duration) {
+	t.Helper()
+
+	later := time.Now().Add(by)
+	assert.NoError(t, os.Chtimes(path, later, later))
+}
@@ -3,13 +3,22 @@ package sbpfx
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx/calendar"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -32,189 +41,545 @@ const (
 // It is used only to corroborate — a body that passes the signature check but
 // whose Content-Type explicitly advertises a non-PDF type is still rejected,
 // which catches an HTML soft-404 even if its body were to start with %PDF.
-func looksLikePDF(contentType string, content []byte) bool {
+//
+// If it isn't, reason says why, for the client's logs.
+func looksLikePDF(contentType string, content []byte) (bool, string) {
 	if !bytes.HasPrefix(content, []byte(pdfSignature)) {
-		return false
+		return false, "body lacks the %PDF signature"
 	}
 
 	ct := strings.ToLower(contentType)
 	if ct != "" && !strings.Contains(ct, pdfContentType) {
-		return false
+		return false, "Content-Type is not " + pdfContentType
 	}
 
-	return true
+	return true, ""
 }
 
-// SBP has hosted the daily sheets under several naming schemes as it migrated
-// to the /assets/document host. ratePaths returns the candidate URL paths for a
-// date, in priority order:
-//   - migrationOverrides: transition-window sheets with irregular names
-//   - after 2026-07-02: current prefix + DD-month-YYYY (e.g. 14-july-2026)
-//     AND the bare DD-Mon-YY name (e.g. 17-Jul-26) — see below
-//   - after 2026-05-31: bare DD-Mon-YY (e.g. 23-Jun-26)
-//   - earlier: prefix + DD-Mon-YY (e.g. 27-Aug-25)
-//
-// From July 2026 onward SBP posts the same daily sheet under either the long
-// prefixed name or the bare DD-Mon-YY name with no discernible pattern (e.g.
-// .../mark-to-market-revaluation-exchange-rate-14-july-2026.pdf on one day and
-// .../17-Jul-26.pdf on another), so the current era yields both candidates and
-// the caller tries each until one resolves to a real PDF.
-//
-// The boundaries are the last day of the previous scheme so the checks can use
-// date.After; dates are day-truncated by ForDate/ForTime.
-var (
-	lastLegacyDay   = time.Date(2026, time.July, 2, 0, 0, 0, 0, time.UTC) // current scheme starts the next day
-	lastPrefixedDay = time.Date(2026, time.May, 31, 0, 0, 0, 0, time.UTC) // bare scheme starts the next day
-)
-
-// migrationOverrides holds the transition-window sheets that SBP uploaded with
-// irregular names that fit none of the date-based schemes. Keyed by YYYY-MM-DD.
-var migrationOverrides = map[string]string{
-	"2026-06-30": "/30-Jun-26_1.pdf",            // bare date with a _1 suffix
-	"2026-07-02": ratePrefix + "-02-Jul-26.pdf", // prefix but legacy date style
+type Client struct {
+	httpClient *httpr.Client
+	location   *time.Location
+	calendar   *calendar.Calendar
+	cache      Cache
+	store      Store
+	resolver   Resolver
+	probe      Probe
+	metrics    *metrics
+	tracer     trace.Tracer
+	logger     *slog.Logger
+	sheets     *sheetCache
+	flights    flightGroup
 }
 
-// ratePaths builds the candidate URL paths for the exchange rate PDF of the
-// given date, in priority order. Most eras resolve to a single deterministic
-// path; the current era (July 2026 onward) returns both the long prefixed name
-// and the bare DD-Mon-YY name because SBP uses them interchangeably.
-func ratePaths(date time.Time) []string {
-	if override, ok := migrationOverrides[date.Format("2006-01-02")]; ok {
-		return []string{override}
+// New creates a Client. It accepts both httpr options, which configure the
+// underlying HTTP client, and sbpfx ClientOptions such as WithLocation.
+func New(options ...httpr.ClientOption) *Client {
+	c := &Client{
+		httpClient: nil,
+		location:   pakistanTime,
+		calendar:   calendar.Pakistan(),
+		cache:      nil,
+		store:      nil,
+		resolver:   DefaultResolver(),
+		probe:      0,
+		metrics:    nil,
+		tracer:     defaultTracer(),
+		logger:     discardLogger,
+		sheets:     nil,
+		flights:    flightGroup{mu: sync.Mutex{}, flights: nil},
 	}
 
-	// Legacy dates: DD-Mon-YY, e.g. 27-Aug-25.
-	legacyDate := fmt.Sprintf("%02d-%s-%02d",
-		date.Day(),
-		date.Format("Jan"),
-		date.Year()%YearModulo, // Last 2 digits of year
-	)
+	var httpOpts []httpr.ClientOption
+	for _, opt := range options {
+		if clientOpt, ok := opt.(ClientOption); ok {
+			clientOpt(c)
+			continue
+		}
+		httpOpts = append(httpOpts, opt)
+	}
 
-	switch {
-	case date.After(lastLegacyDay):
-		// Current era: SBP posts the sheet under either the long prefixed name
-		// (prefix + DD-month-YYYY, e.g. 14-july-2026) or the bare DD-Mon-YY
-		// name (e.g. 17-Jul-26), so try both.
-		longPath := fmt.Sprintf("%s-%02d-%s-%d.pdf",
-			ratePrefix,
-			date.Day(),
-			strings.ToLower(date.Format("January")),
-			date.Year(),
-		)
-		return []string{longPath, "/" + legacyDate + ".pdf"}
-	case date.After(lastPrefixedDay):
-		// Recent legacy window: bare name, e.g. /23-Jun-26.pdf.
-		return []string{"/" + legacyDate + ".pdf"}
-	default:
-		// Older archive: prefix + DD-Mon-YY, e.g. .../rate-27-Aug-25.pdf.
-		return []string{ratePrefix + "-" + legacyDate + ".pdf"}
+	c.httpClient = httpr.NewClient(httpOpts...)
+
+	return c
+}
+
+// Today returns today's business date in the client's location, as midnight
+// UTC. Sheets for earlier dates never change.
+func (c *Client) Today() time.Time {
+	return businessDate(time.Now(), c.location)
+}
+
+// config applies per-request options on top of the client's defaults.
+func (c *Client) config(opts []Option) (*option, error) {
+	cfg := defaultConfig(c.location)
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
 	}
+
+	return cfg, nil
 }
 
-type Client struct {
-	httpClient *httpr.Client
+// fetchRateSheet returns the PDF for the given date under a span.
+func (c *Client) fetchRateSheet(ctx context.Context, date time.Time) (*CacheEntry, error) {
+	ctx, span := c.tracer.Start(ctx, "sbpfx.fetchRateSheet", trace.WithAttributes(attrDate.String(date.Format("2006-01-02"))))
+	defer span.End()
+
+	entry, err := c.resolveRateSheet(ctx, date)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attrURL.String(entry.URL), attrBytes.Int(len(entry.Content)))
+	return entry, nil
 }
 
-func New(options ...httpr.ClientOption) *Client {
-	opts := append(
-		[]httpr.ClientOption{
-			httpr.BaseURL(BaseURL),
-		},
-		options...,
-	)
+// resolveRateSheet returns the PDF for the given date, from the client's cache
+// if it has one, otherwise from the first candidate URL that resolves to a
+// real rate-sheet PDF. If no candidate yields a sheet, the returned
+// *LookupError records every URL tried and why each was rejected.
+//
+// Earlier sheets never change, so a cached one is used as is. Today's sheet can
+// be re-posted during the day, so a cached copy is revalidated with a
+// conditional GET and only downloaded again if SBP reports it changed. If SBP
+// can't be reached to ask, the cached copy is used.
+func (c *Client) resolveRateSheet(ctx context.Context, date time.Time) (*CacheEntry, error) {
+	if c.cache == nil {
+		return c.downloadRateSheet(ctx, date)
+	}
+
+	cached, ok, err := c.cache.Get(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate sheet for %s from cache: %w", date.Format("2006-01-02"), err)
+	}
+	c.metrics.cacheLookup("pdf", ok)
+	trace.SpanFromContext(ctx).SetAttributes(attrCacheHit.Bool(ok))
+	if ok && date.Before(c.Today()) {
+		c.logger.DebugContext(ctx, "using cached rate sheet", slog.String("date", date.Format("2006-01-02")), slog.String("url", cached.URL))
+		return cached, nil
+	}
 
-	return &Client{
-		httpClient: httpr.NewClient(opts...),
+	var entry *CacheEntry
+	if ok {
+		c.logger.DebugContext(ctx, "revalidating cached rate sheet", slog.String("date", date.Format("2006-01-02")), slog.String("url", cached.URL))
+		var failure *CandidateError
+		entry, failure = c.fetchPDF(ctx, date, candidate{url: cached.URL, override: false}, cached)
+		if failure != nil && !errors.Is(failure, ErrSheetNotFound) {
+			// SBP couldn't be asked, which says nothing about the sheet, so
+			// the cached copy stands until it can be.
+			c.logger.WarnContext(ctx, "failed to revalidate cached rate sheet, using cached copy",
+				slog.String("date", date.Format("2006-01-02")), slog.String("url", cached.URL), slog.Any("error", failure))
+			return cached, nil
+		}
+		// Otherwise, if the sheet has moved or gone, fall back to searching
+		// the candidates.
 	}
+	if entry == nil {
+		if entry, err = c.downloadRateSheet(ctx, date); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok && entry.SHA256 == cached.SHA256 {
+		entry.ChangedAt = cached.ChangedAt
+	}
+
+	// The sheet is already in hand, so a failure to cache it (e.g. a full or
+	// read-only disk) costs only a download next time.
+	if err := c.cache.Put(ctx, entry); err != nil {
+		c.logger.WarnContext(ctx, "failed to cache rate sheet", slog.String("date", date.Format("2006-01-02")), slog.String("url", entry.URL), slog.Any("error", err))
+	}
+
+	return entry, nil
 }
 
-// fetchRateSheet downloads the first candidate URL that resolves to a real
-// rate-sheet PDF for the given date. It returns the PDF bytes and the full URL
-// that served them. When several candidates are tried, the error from the
-// last candidate is returned if none are fetched.
-func (c *Client) fetchRateSheet(ctx context.Context, date time.Time) ([]byte, string, error) {
-	var lastErr error
-	for _, path := range ratePaths(date) {
-		content, err := c.fetchPDF(ctx, path)
+// downloadRateSheet tries each candidate URL for the date in priority order and
+// returns the first real rate-sheet PDF. Under WithParallelProbe the
+// candidates are requested all at once.
+func (c *Client) downloadRateSheet(ctx context.Context, date time.Time) (*CacheEntry, error) {
+	candidates, err := c.candidates(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cand := range candidates {
+		if cand.override {
+			c.logger.DebugContext(ctx, "using migration override", slog.String("date", date.Format("2006-01-02")), slog.String("url", cand.url))
+		}
+	}
+	if c.probe != 0 && len(candidates) > 1 {
+		return c.probeRateSheet(ctx, date, candidates)
+	}
+
+	lookupErr := &LookupError{Date: date, URLs: nil, Failures: nil}
+	for _, cand := range candidates {
+		entry, err := c.fetchPDF(ctx, date, cand, nil)
 		if err != nil {
-			lastErr = err
+			lookupErr.URLs = append(lookupErr.URLs, err.URL)
+			lookupErr.Failures = append(lookupErr.Failures, err)
 			continue
 		}
 
-		return content, BaseURL + path, nil
+		return entry, nil
 	}
 
-	return nil, "", lastErr
+	return nil, lookupErr
 }
 
 // fetchPDF issues a single GET for a candidate path and returns its body only
-// if the response is a real rate-sheet PDF.
-func (c *Client) fetchPDF(ctx context.Context, path string) ([]byte, error) {
-	resp, err := c.httpClient.Get(ctx, path)
+// if the response is a real rate-sheet PDF. If cached is set, the request is
+// conditional on its validators, and a 304 Not Modified returns cached with
+// CheckedAt updated. The returned entry records the response's validators.
+func (c *Client) fetchPDF(ctx context.Context, date time.Time, cand candidate, cached *CacheEntry) (*CacheEntry, *CandidateError) {
+	candidateURL, scheme := cand.url, candidateScheme(cand)
+
+	var opts []httpr.RequestOption
+	if cached != nil && cached.ETag != "" {
+		opts = append(opts, httpr.Header("If-None-Match", cached.ETag))
+	}
+	if cached != nil && cached.LastModified != "" {
+		opts = append(opts, httpr.Header("If-Modified-Since", cached.LastModified))
+	}
+
+	ctx, span := c.tracer.Start(ctx, "sbpfx.fetchPDF", trace.WithAttributes(
+		attrDate.String(date.Format("2006-01-02")),
+		attrURL.String(candidateURL),
+		attrScheme.String(scheme),
+		attrConditional.Bool(cached != nil),
+	))
+	defer span.End()
+
+	start := time.Now()
+	record := func(outcome string, status, size int) {
+		elapsed := time.Since(start)
+		c.metrics.candidate(scheme, outcome, elapsed, size)
+		c.logger.DebugContext(ctx, "tried candidate URL",
+			slog.String("date", date.Format("2006-01-02")),
+			slog.String("url", candidateURL),
+			slog.String("scheme", scheme),
+			slog.Bool("conditional", cached != nil),
+			slog.Int("status", status),
+			slog.String("outcome", outcome),
+			slog.Int("bytes", size),
+			slog.Duration("elapsed", elapsed),
+		)
+		span.SetAttributes(attrOutcome.String(outcome), attrBytes.Int(size))
+		if status != 0 {
+			span.SetAttributes(attrStatusCode.Int(status), attrSoft404.Bool(outcome == outcomeNotFound && status == HTTPStatusOK))
+		}
+	}
+
+	resp, err := c.httpClient.Get(ctx, candidateURL, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download PDF: %w", err)
+		if ctx.Err() != nil {
+			// Cancelled, e.g. because a parallel probe found the sheet elsewhere.
+			record(outcomeCanceled, 0, 0)
+			return nil, &CandidateError{URL: candidateURL, StatusCode: 0, Err: fmt.Errorf("failed to download PDF: %w", err)}
+		}
+		record(outcomeError, 0, 0)
+		err = fmt.Errorf("failed to download PDF: %w", err)
+		recordError(span, err)
+		c.logger.WarnContext(ctx, "candidate request failed", slog.String("url", candidateURL), slog.Any("error", err))
+		return nil, &CandidateError{URL: candidateURL, StatusCode: 0, Err: err}
 	}
 	defer resp.Body.Close()
 
+	now := time.Now().UTC()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		record(outcomeNotModified, resp.StatusCode, 0)
+		revalidated := *cached
+		revalidated.CheckedAt = now
+		return &revalidated, nil
+	}
+
 	if resp.StatusCode != HTTPStatusOK {
-		return nil, fmt.Errorf("PDF not found: status %d for path: %s", resp.StatusCode, path)
+		record(outcomeNotFound, resp.StatusCode, 0)
+		return nil, &CandidateError{
+			URL:        candidateURL,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%w: status %d for URL: %s", ErrSheetNotFound, resp.StatusCode, candidateURL),
+		}
 	}
 
 	content, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read PDF: %w", err)
+		record(outcomeError, resp.StatusCode, len(content))
+		err = fmt.Errorf("failed to read PDF: %w", err)
+		recordError(span, err)
+		c.logger.WarnContext(ctx, "candidate request failed", slog.String("url", candidateURL), slog.Any("error", err))
+		return nil, &CandidateError{URL: candidateURL, StatusCode: resp.StatusCode, Err: err}
 	}
 
-	if !looksLikePDF(resp.Header.Get("Content-Type"), content) {
-		return nil, fmt.Errorf("PDF not found: no rate sheet available for path: %s", path)
+	// SBP answers some missing sheets with a 200 HTML page (a soft 404).
+	if ok, reason := looksLikePDF(resp.Header.Get("Content-Type"), content); !ok {
+		record(outcomeNotFound, resp.StatusCode, len(content))
+		c.logger.InfoContext(ctx, "soft 404: candidate URL answered 200 without a rate sheet",
+			slog.String("url", candidateURL),
+			slog.String("content_type", resp.Header.Get("Content-Type")),
+			slog.Int("bytes", len(content)),
+			slog.String("reason", reason),
+		)
+		return nil, &CandidateError{
+			URL:        candidateURL,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%w: no rate sheet available for URL: %s", ErrSheetNotFound, candidateURL),
+		}
 	}
 
-	return content, nil
+	record(outcomeOK, resp.StatusCode, len(content))
+	sum := sha256.Sum256(content)
+	return &CacheEntry{
+		Date:         date,
+		URL:          candidateURL,
+		SHA256:       hex.EncodeToString(sum[:]),
+		FetchedAt:    now,
+		CheckedAt:    now,
+		ChangedAt:    now,
+		ETag:         resp.Header.Get("Etag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Content:      content,
+	}, nil
 }
 
-func (c *Client) GetExchangeRates(ctx context.Context, opts ...Option) (map[Currency]*ExchangeRate, error) {
-	cfg := defaultConfig()
-	for _, opt := range opts {
-		if err := opt(cfg); err != nil {
-			return nil, fmt.Errorf("failed to apply option: %w", err)
+// GetRateSheet fetches and parses the rate sheet for a date, returning every
+// currency row in sheet order along with the sheet's metadata.
+func (c *Client) GetRateSheet(ctx context.Context, opts ...Option) (*RateSheet, error) {
+	return c.tracedRateSheet(ctx, "sbpfx.GetRateSheet", opts)
+}
+
+// tracedRateSheet fetches the sheet selected by opts under a span with the
+// given name, so each public method gets a single span of its own.
+func (c *Client) tracedRateSheet(ctx context.Context, name string, opts []Option, attrs ...attribute.KeyValue) (*RateSheet, error) {
+	cfg, err := c.config(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs = append(attrs, attrDate.String(cfg.date.Format("2006-01-02")), attrLookback.Int(cfg.lookback))
+	ctx, span := c.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	sheet, err := c.rateSheet(ctx, cfg.date, cfg.lookback)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attrSheetDate.String(sheet.Date.Format("2006-01-02")),
+		attrURL.String(sheet.URL),
+		attrCurrencies.Int(len(sheet.Rates)),
+	)
+	return sheet, nil
+}
+
+// rateSheet fetches the sheet for date, walking back up to lookback days if
+// lookback is positive.
+func (c *Client) rateSheet(ctx context.Context, date time.Time, lookback int) (*RateSheet, error) {
+	if lookback == 0 {
+		return c.getRateSheet(ctx, date)
+	}
+
+	return c.latestRateSheet(ctx, date, lookback)
+}
+
+// latestRateSheet walks back from date, one day at a time, until it finds a
+// published, parseable sheet or has tried lookback earlier days. Days the
+// client's calendar marks as weekends or holidays are skipped without a
+// request. Missing and malformed sheets are skipped; any other failure
+// (network, context) stops the walk, since an earlier date would not fare
+// better.
+func (c *Client) latestRateSheet(ctx context.Context, date time.Time, lookback int) (*RateSheet, error) {
+	var skipped []error
+	for offset := range lookback + 1 {
+		candidate := date.AddDate(0, 0, -offset)
+
+		if !c.calendar.IsBusinessDay(candidate) {
+			skipped = append(skipped, fmt.Errorf("%w: %s is not a business day", ErrSheetNotFound, candidate.Format("2006-01-02")))
+			continue
+		}
+
+		sheet, err := c.getRateSheet(ctx, candidate)
+		if err == nil {
+			if offset > 0 {
+				c.logger.InfoContext(ctx, "using earlier rate sheet",
+					slog.String("date", date.Format("2006-01-02")),
+					slog.String("sheet_date", candidate.Format("2006-01-02")),
+					slog.Int("skipped", len(skipped)),
+				)
+			}
+			sheet.RequestedDate = date
+			return sheet, nil
+		}
+
+		if !unavailable(err) {
+			return nil, err
+		}
+		skipped = append(skipped, err)
+	}
+
+	return nil, fmt.Errorf("no rate sheet available from %s back to %s: %w",
+		date.Format("2006-01-02"), date.AddDate(0, 0, -lookback).Format("2006-01-02"), errors.Join(skipped...))
+}
+
+// unavailable reports whether err means SBP has no usable sheet for a date,
+// so an earlier date may be tried: every candidate was missing or malformed.
+// A lookup where any candidate failed for another reason, such as a network
+// error or a cancelled context, says nothing about the date.
+func unavailable(err error) bool {
+	var lookup *LookupError
+	if errors.As(err, &lookup) {
+		for _, failure := range lookup.Failures {
+			if !unavailable(failure.Err) {
+				return false
+			}
+		}
+		return len(lookup.Failures) > 0
+	}
+
+	return errors.Is(err, ErrSheetNotFound) || errors.Is(err, ErrMalformedSheet)
+}
+
+// getRateSheet returns the sheet for exactly one business date, from the
+// in-memory sheet cache if it holds one. Concurrent calls for the same date
+// share a single download and parse.
+func (c *Client) getRateSheet(ctx context.Context, date time.Time) (*RateSheet, error) {
+	key := date.Format("2006-01-02")
+	sheet, ok := c.sheets.get(key, time.Now())
+	if c.sheets != nil {
+		c.metrics.cacheLookup("sheet", ok)
+	}
+	if ok {
+		return sheet.clone(), nil
+	}
+
+	sheet, err := c.flights.do(ctx, key, func(ctx context.Context) (*RateSheet, error) {
+		sheet, err := c.loadRateSheet(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+
+		var expires time.Time
+		if !date.Before(c.Today()) {
+			expires = time.Now().Add(todaySheetTTL)
 		}
+		c.sheets.put(key, sheet, expires)
+
+		return sheet, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	date := cfg.date
+	return sheet.clone(), nil
+}
 
-	content, fullURL, err := c.fetchRateSheet(ctx, date)
+// loadRateSheet fetches and parses the sheet for exactly one business date,
+// or loads it from the client's store if it has one.
+func (c *Client) loadRateSheet(ctx context.Context, date time.Time) (*RateSheet, error) {
+	if c.store != nil && date.Before(c.Today()) {
+		// A store that can't be read is treated as a miss: SBP still has the sheet.
+		stored, ok, err := c.store.LoadSheet(ctx, date)
+		if err != nil {
+			c.logger.WarnContext(ctx, "failed to read stored rate sheet", slog.String("date", date.Format("2006-01-02")), slog.Any("error", err))
+			ok = false
+		}
+		ok = ok && stored.ParserVersion == ParserVersion
+		c.metrics.cacheLookup("store", ok)
+		if ok {
+			c.logger.DebugContext(ctx, "using stored rate sheet", slog.String("date", date.Format("2006-01-02")))
+			return stored, nil
+		}
+	}
+
+	entry, err := c.fetchRateSheet(ctx, date)
 	if err != nil {
 		return nil, err
 	}
+	c.metrics.fetched(date)
 
-	rates, err := parsePDFContent(content, date, fullURL)
+	sheet, err := c.parseRateSheet(ctx, entry, date)
 	if err != nil {
-		// The PDF exists but isn't a parseable rate sheet. SBP posted a few
-		// malformed/unrelated PDFs during the June 2026 migration (e.g.
-		// 2026-06-01, 03, 04, 05); surface a clear, date-tagged error rather
-		// than the raw parser message so callers can distinguish it from a bug.
-		return nil, fmt.Errorf("no valid rate sheet for %s (%s): %w", date.Format("2006-01-02"), fullURL, err)
+		return nil, err
 	}
 
-	return rates, nil
+	sheet.FetchedAt = entry.FetchedAt
+
+	// As with the Cache, the sheet is already in hand, so a failure to store it
+	// costs only a download and parse next time.
+	if c.store != nil {
+		if err := c.store.SaveSheet(ctx, sheet); err != nil {
+			c.logger.WarnContext(ctx, "failed to store rate sheet", slog.String("date", date.Format("2006-01-02")), slog.Any("error", err))
+		}
+	}
+
+	return sheet, nil
+}
+
+// parseRateSheet parses a downloaded PDF under a span, recording its size and
+// how many currencies it held.
+func (c *Client) parseRateSheet(ctx context.Context, entry *CacheEntry, date time.Time) (*RateSheet, error) {
+	_, span := c.tracer.Start(ctx, "sbpfx.parsePDFContent", trace.WithAttributes(
+		attrDate.String(date.Format("2006-01-02")),
+		attrURL.String(entry.URL),
+		attrBytes.Int(len(entry.Content)),
+		attrParser.String(ParserVersion),
+	))
+	defer span.End()
+
+	start := time.Now()
+	sheet, err := parseSheet(entry.Content, date, entry.URL, c.logger.With(slog.String("url", entry.URL)))
+	c.metrics.parsed(time.Since(start), err)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attrCurrencies.Int(len(sheet.Rates)))
+	return sheet, nil
+}
+
+// GetExchangeRates fetches the rate sheet for a date and returns its rows keyed
+// by currency. Use GetRateSheet for sheet order and metadata.
+func (c *Client) GetExchangeRates(ctx context.Context, opts ...Option) (map[Currency]*ExchangeRate, error) {
+	sheet, err := c.tracedRateSheet(ctx, "sbpfx.GetExchangeRates", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return sheet.Map(), nil
 }
 
 func (c *Client) GetExchangeRate(ctx context.Context, currency Currency, opts ...Option) (*ExchangeRate, error) {
-	rates, err := c.GetExchangeRates(ctx, opts...)
+	sheet, err := c.tracedRateSheet(ctx, "sbpfx.GetExchangeRate", opts, attrCurrency.String(currency.String()))
 	if err != nil {
 		return nil, err
 	}
 
-	rate, exists := rates[currency]
+	return sheetRate(sheet, currency)
+}
+
+// sheetRate returns a currency's row, or ErrCurrencyNotFound if the sheet does
+// not quote it.
+func sheetRate(sheet *RateSheet, currency Currency) (*ExchangeRate, error) {
+	rate, exists := sheet.Rate(currency)
 	if !exists {
-		return nil, fmt.Errorf("exchange rate for %s not found", currency)
+		return nil, fmt.Errorf("exchange rate for %s on %s: %w", currency, sheet.Date.Format("2006-01-02"), ErrCurrencyNotFound)
 	}
 
 	return rate, nil
 }
 
+// GetUrl returns the primary candidate URL of the rate sheet for a date: the
+// first URL the client's Resolver yields, or "" if it yields none or fails.
+//
+// It resolves under context.Background, so with a resolver that reads the
+// network, such as a DiscoveryResolver, it may block on SBP with no deadline.
+// Use GetUrlContext to bound it.
 func (c *Client) GetUrl(opts ...Option) string {
-	cfg := defaultConfig()
+	cfg := defaultConfig(c.location)
 	for _, opt := range opts {
 		if err := opt(cfg); err != nil {
 			// For this method, we'll ignore errors and use default config
@@ -223,25 +588,60 @@ func (c *Client) GetUrl(opts ...Option) string {
 		}
 	}
 
-	date := cfg.date
+	url, err := c.primaryURL(context.Background(), cfg.date)
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// GetUrlContext is GetUrl under ctx. It returns an error if an option is
+// invalid, or if the client's Resolver fails or yields no URL
+// (ErrSheetNotFound).
+func (c *Client) GetUrlContext(ctx context.Context, opts ...Option) (string, error) {
+	cfg, err := c.config(opts)
+	if err != nil {
+		return "", err
+	}
 
-	// ratePaths always returns at least one candidate; [0] is the primary name.
-	// In the current era SBP may instead post under the bare fallback name, so
-	// callers that need the URL that actually served a sheet should use
-	// GetExchangeRates (which reports the resolved URL) rather than GetUrl.
-	return BaseURL + ratePaths(date)[0]
+	return c.primaryURL(ctx, cfg.date)
 }
 
-// DownloadRateSheet downloads the exchange rate PDF to the specified file path.
-func (c *Client) DownloadRateSheet(ctx context.Context, path string, opts ...Option) error {
-	cfg := defaultConfig()
-	for _, opt := range opts {
-		if err := opt(cfg); err != nil {
-			return fmt.Errorf("failed to apply option: %w", err)
-		}
+// primaryURL returns the first of the client resolver's candidates for date.
+//
+// In the current era SBP may instead post under the bare fallback name, so
+// callers that need the URL that actually served a sheet should use
+// GetExchangeRates (which reports the resolved URL) rather than GetUrl.
+func (c *Client) primaryURL(ctx context.Context, date time.Time) (string, error) {
+	candidates, err := c.candidates(ctx, date)
+	if err != nil {
+		return "", err
+	}
+	return candidates[0].url, nil
+}
+
+// FetchRateSheet downloads the exchange rate PDF for a date without parsing
+// it. The entry holds the PDF bytes along with the URL that served them and
+// their SHA-256, and goes through the client's Cache like any other lookup.
+// LatestAvailable is ignored: the sheet is fetched for the requested date only.
+func (c *Client) FetchRateSheet(ctx context.Context, opts ...Option) (*CacheEntry, error) {
+	cfg, err := c.config(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := c.fetchRateSheet(ctx, cfg.date)
+	if err != nil {
+		return nil, err
 	}
+	c.metrics.fetched(cfg.date)
+
+	return entry, nil
+}
 
-	content, _, err := c.fetchRateSheet(ctx, cfg.date)
+// DownloadRateSheet downloads the exchange rate PDF to the specified file path.
+func (c *Client) DownloadRateSheet(ctx context.Context, path string, opts ...Option) error {
+	entry, err := c.FetchRateSheet(ctx, opts...)
 	if err != nil {
 		return err
 	}
@@ -252,7 +652,7 @@ func (c *Client) DownloadRateSheet(ctx context.Context, path string, opts ...Opt
 	}
 	defer file.Close()
 
-	if _, err = file.Write(content); err != nil {
+	if _, err = file.Write(entry.Content); err != nil {
 		return fmt.Errorf("failed to write PDF to file %s: %w", path, err)
 	}
 
@@ -1,13 +1,18 @@
 package sbpfx_test
 
 import (
+	"errors"
+	"net"
+	"net/http"
 	"os"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/mistermoe/httpr"
 	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/calendar"
 	"github.com/mistermoe/sbpfx/vcr"
 	"gopkg.in/dnaeon/go-vcr.v3/recorder"
 )
@@ -19,6 +24,30 @@ func bootstrap(_ *testing.T, mode vcr.Mode, rec *recorder.Recorder) *sbpfx.Clien
 	return sbpfx.New(httpr.HTTPClient(*recorder))
 }
 
+// fixtureServer replays recorded responses to clients under test.
+type fixtureServer struct {
+	*vcr.Server
+}
+
+// newFixtureClient returns a client whose requests are all served by a
+// vcr.Server replaying the named cassettes from fixtures/.
+func newFixtureClient(t *testing.T, cassettes ...string) (*sbpfx.Client, *fixtureServer) {
+	t.Helper()
+
+	paths := make([]string, 0, len(cassettes))
+	for _, name := range cassettes {
+		paths = append(paths, "fixtures/"+name)
+	}
+	fixtures := &fixtureServer{Server: vcr.NewServer(t, paths...)}
+
+	return fixtures.client(), fixtures
+}
+
+// client returns a new client served by the fixture server.
+func (f *fixtureServer) client(options ...httpr.ClientOption) *sbpfx.Client {
+	return sbpfx.New(append([]httpr.ClientOption{httpr.HTTPClient(http.Client{Transport: f.Transport()})}, options...)...)
+}
+
 func TestGetExchangeRates(t *testing.T) {
 	vcr.Test(t, testMode, bootstrap, func(t *testing.T, client *sbpfx.Client, c vcr.Cassette) {
 		rate, err := client.GetExchangeRate(t.Context(), sbpfx.USD, sbpfx.ForDate("2025-08-27"))
@@ -29,9 +58,75 @@ func TestGetExchangeRates(t *testing.T) {
 		assert.NotZero(t, rate.Ready)
 		assert.NotZero(t, rate.Date)
 		assert.NotZero(t, rate.URL)
+
+		// Every tenor column on the sheet is populated for the row.
+		assert.Equal(t, "281.8289", rate.Ready)
+		assert.Equal(t, "282.0792", rate.OneWeek)
+		assert.Equal(t, "282.3819", rate.TwoWeek)
+		assert.Equal(t, "283.1285", rate.OneMonth)
+		assert.Equal(t, "284.3818", rate.TwoMonth)
+		assert.Equal(t, "285.5005", rate.ThreeMonth)
+		assert.Equal(t, "286.6355", rate.FourMonth)
+		assert.Equal(t, "287.6732", rate.FiveMonth)
+		assert.Equal(t, "288.8673", rate.SixMonth)
+		assert.Equal(t, "291.4649", rate.NineMonth)
+		assert.Equal(t, "294.2690", rate.OneYear)
+		assert.Equal(t, rate.OneYear, rate.Tenor(sbpfx.TenorOneYear))
 	})
 }
 
+func TestGetExchangeRatesTenors(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	rates, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	// Each currency gets its own row, not its neighbour's forward rates.
+	eur := rates[sbpfx.EUR]
+	assert.NotZero(t, eur)
+	assert.Equal(t, "326.9215", eur.Ready)
+	assert.Equal(t, "348.0810", eur.OneYear)
+
+	// SBP prints 0.0000 for tenors it doesn't quote; those stay empty.
+	bdt := rates[sbpfx.BDT]
+	assert.NotZero(t, bdt)
+	assert.Equal(t, "2.3153", bdt.Ready)
+	assert.Equal(t, "", bdt.OneWeek)
+	assert.Equal(t, "", bdt.OneYear)
+
+	for _, rate := range rates {
+		assert.NotZero(t, rate.Ready, "%s should have a spot rate", rate.Currency)
+	}
+}
+
+func TestGetRateSheet(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2025-08-27", sheet.Date.Format("2006-01-02"))
+	assert.Equal(t, "2025-08-27", sheet.PrintedDate.Format("2006-01-02"))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", sheet.URL)
+	assert.Equal(t, 64, len(sheet.SHA256))
+	assert.NotZero(t, sheet.FetchedAt)
+	assert.Equal(t, sbpfx.ParserVersion, sheet.ParserVersion)
+
+	// Rows keep the order SBP printed them in.
+	assert.True(t, len(sheet.Rates) > 2)
+	assert.Equal(t, sbpfx.USD, sheet.Rates[0].Currency)
+	assert.Equal(t, sbpfx.EUR, sheet.Rates[1].Currency)
+	assert.Equal(t, sbpfx.JPY, sheet.Rates[2].Currency)
+
+	usd, ok := sheet.Rate(sbpfx.USD)
+	assert.True(t, ok)
+	assert.Equal(t, "281.8289", usd.Ready)
+	assert.Equal(t, len(sheet.Rates), len(sheet.Map()))
+
+	assert.NotZero(t, sheet.Notes)
+	assert.Contains(t, sheet.Notes[0], "Authorized Dealers")
+}
+
 func TestDownloadRateSheet(t *testing.T) {
 	vcr.Test(t, testMode, bootstrap, func(t *testing.T, client *sbpfx.Client, c vcr.Cassette) {
 		// Create a temporary file path
@@ -59,6 +154,19 @@ func TestGetExchangeRatesFutureDate(t *testing.T) {
 
 		// The error should indicate the PDF was not found
 		assert.Contains(t, err.Error(), "PDF not found", "Error should mention PDF not found")
+		assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+		assert.NotIsError(t, err, sbpfx.ErrMalformedSheet)
+
+		// Both candidate names for the date were tried and soft-404'd.
+		var lookupErr *sbpfx.LookupError
+		assert.True(t, errors.As(err, &lookupErr))
+		assert.Equal(t, "2030-12-25", lookupErr.Date.Format("2006-01-02"))
+		assert.Equal(t, []string{
+			"https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-25-december-2030.pdf",
+			"https://www.sbp.org.pk/assets/document/25-Dec-30.pdf",
+		}, lookupErr.URLs)
+		assert.Equal(t, 2, len(lookupErr.Failures))
+		assert.Equal(t, 200, lookupErr.Failures[0].StatusCode)
 	})
 }
 
@@ -73,6 +181,9 @@ func TestGetExchangeRatesDualFormat(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotZero(t, rate)
 		assert.Equal(t, "https://www.sbp.org.pk/assets/document/17-Jul-26.pdf", rate.URL)
+		assert.Equal(t, "277.9612", rate.Ready)
+		assert.Equal(t, "278.3266", rate.OneWeek)
+		assert.Equal(t, "292.1805", rate.OneYear)
 	})
 }
 
@@ -84,9 +195,109 @@ func TestGetExchangeRatesMalformedSheet(t *testing.T) {
 		_, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-06-01"))
 		assert.Error(t, err, "malformed sheet should error")
 		assert.Contains(t, err.Error(), "no valid rate sheet", "error should be the domain error")
+		assert.IsError(t, err, sbpfx.ErrMalformedSheet)
+		assert.NotIsError(t, err, sbpfx.ErrSheetNotFound)
 	})
 }
 
+func TestGetExchangeRateCurrencyNotFound(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	// GNH is a known currency code that the 2025-08-27 sheet doesn't quote.
+	rate, err := client.GetExchangeRate(t.Context(), sbpfx.GNH, sbpfx.ForDate("2025-08-27"))
+	assert.Zero(t, rate)
+	assert.IsError(t, err, sbpfx.ErrCurrencyNotFound)
+	assert.NotIsError(t, err, sbpfx.ErrSheetNotFound)
+}
+
+func TestLatestAvailable(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRates")
+
+	// Nothing is published on the 29th or 28th, so the client walks back to
+	// the 27th and reports that it served that date.
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-29"), sbpfx.LatestAvailable(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-08-29", sheet.RequestedDate.Format("2006-01-02"))
+	assert.Equal(t, "2025-08-27", sheet.Date.Format("2006-01-02"))
+
+	rate, err := client.GetExchangeRate(t.Context(), sbpfx.USD, sbpfx.ForDate("2025-08-29"), sbpfx.LatestAvailable(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-08-27", rate.Date.Format("2006-01-02"))
+	assert.Equal(t, "281.8289", rate.Ready)
+
+	// The walk stops after the lookback window.
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-29"), sbpfx.LatestAvailable(1))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	_, err = client.GetRateSheet(t.Context(), sbpfx.LatestAvailable(-1))
+	assert.Error(t, err)
+}
+
+func TestLatestAvailableSkipsNonBusinessDays(t *testing.T) {
+	client, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+
+	// Walking back from Monday 2025-09-01 skips the weekend without asking SBP.
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-09-01"), sbpfx.LatestAvailable(5))
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-08-27", sheet.Date.Format("2006-01-02"))
+	assert.Equal(t, []string{
+		"/assets/document/mark-to-market-revaluation-exchange-rate-01-Sep-25.pdf",
+		"/assets/document/mark-to-market-revaluation-exchange-rate-29-Aug-25.pdf",
+		"/assets/document/mark-to-market-revaluation-exchange-rate-28-Aug-25.pdf",
+		"/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf",
+	}, fixtures.Requests())
+
+	// A window of only weekend days is reported as not found.
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-31"), sbpfx.LatestAvailable(1))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	assert.Zero(t, fixtures.Requests())
+
+	// An explicitly requested weekend date is still fetched.
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-31"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	// A calendar with no weekend tries every day.
+	client = fixtures.client(sbpfx.WithCalendar(calendar.New()))
+	_, err = client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-09-01"), sbpfx.LatestAvailable(5))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(fixtures.Requests()))
+}
+
+func TestLatestAvailableMalformedSheet(t *testing.T) {
+	client, _ := newFixtureClient(t, "TestGetExchangeRatesMalformedSheet")
+
+	// 2026-06-02 has no sheet and 2026-06-01's is malformed; the error keeps
+	// both reasons distinguishable.
+	_, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2026-06-02"), sbpfx.LatestAvailable(1))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+	assert.IsError(t, err, sbpfx.ErrMalformedSheet)
+}
+
+func TestLatestAvailableStopsOnLookupFailure(t *testing.T) {
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+
+	// A mirror that can't be reached is tried before SBP's own URL.
+	transport := vcr.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == "mirror.example" {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Source: nil, Addr: nil, Err: syscall.ECONNREFUSED}
+		}
+		return fixtures.Transport().RoundTrip(r)
+	})
+	client := sbpfx.New(
+		httpr.HTTPClient(http.Client{Transport: transport}),
+		sbpfx.WithResolver(sbpfx.ChainResolvers(staticResolver("https://mirror.example/sheet.pdf"), sbpfx.DefaultResolver())),
+	)
+
+	// SBP has nothing for the 28th, but the mirror might have: the walk stops
+	// there rather than serving the 27th.
+	_, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-28"), sbpfx.LatestAvailable(1))
+	assert.IsError(t, err, syscall.ECONNREFUSED)
+	assert.Equal(t, []string{
+		"/assets/document/mark-to-market-revaluation-exchange-rate-28-Aug-25.pdf",
+	}, fixtures.Requests())
+}
+
 func TestForDateAndForTime(t *testing.T) {
 	vcr.Test(t, testMode, bootstrap, func(t *testing.T, client *sbpfx.Client, c vcr.Cassette) {
 		// Test ForDate with string format
@@ -139,6 +350,39 @@ func TestGetUrlDateFormats(t *testing.T) {
 	}
 }
 
+func TestForTimeUsesPakistanTime(t *testing.T) {
+	const base = "https://www.sbp.org.pk/assets/document"
+
+	// 20:00 UTC on the 26th is already 01:00 on the 27th in Karachi (UTC+5).
+	evening := time.Date(2025, 8, 26, 20, 0, 0, 0, time.UTC)
+
+	client := sbpfx.New()
+	assert.Equal(t, base+"/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", client.GetUrl(sbpfx.ForTime(evening)))
+
+	// The location can be overridden, e.g. to keep the old UTC behaviour.
+	utcClient := sbpfx.New(sbpfx.WithLocation(time.UTC))
+	assert.Equal(t, base+"/mark-to-market-revaluation-exchange-rate-26-Aug-25.pdf", utcClient.GetUrl(sbpfx.ForTime(evening)))
+
+	// ForDate names a calendar day, so it is unaffected by the location.
+	assert.Equal(t, client.GetUrl(sbpfx.ForDate("2025-08-26")), utcClient.GetUrl(sbpfx.ForDate("2025-08-26")))
+
+	// A nil location keeps the default rather than panicking.
+	nilClient := sbpfx.New(sbpfx.WithLocation(nil))
+	assert.Equal(t, client.GetUrl(sbpfx.ForTime(evening)), nilClient.GetUrl(sbpfx.ForTime(evening)))
+	assert.Equal(t, client.Today(), nilClient.Today())
+}
+
+func TestParseCurrency(t *testing.T) {
+	for code, want := range map[string]sbpfx.Currency{"USD": sbpfx.USD, "eur": sbpfx.EUR, " gbp\n": sbpfx.GBP, "PKR": sbpfx.PKR} {
+		currency, err := sbpfx.ParseCurrency(code)
+		assert.NoError(t, err)
+		assert.Equal(t, want, currency)
+	}
+
+	_, err := sbpfx.ParseCurrency("XYZ")
+	assert.EqualError(t, err, `unknown currency "XYZ"`)
+}
+
 func TestForDateInvalidFormat(t *testing.T) {
 	client := sbpfx.New()
 
@@ -151,3 +395,71 @@ func TestForDateInvalidFormat(t *testing.T) {
 	assert.True(t, len(url1) > 0, "Should still generate a URL even with invalid date")
 	assert.True(t, len(url2) > 0, "Should generate a URL with default date")
 }
+
+func TestGetExchangeRatesRange(t *testing.T) {
+	client, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+
+	from := time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	results, err := client.GetExchangeRatesRange(t.Context(), from, to, sbpfx.Workers(2))
+	assert.NoError(t, err)
+
+	// Only Monday to Friday are fetched, in date order, and the missing days
+	// don't stop the 27th from being returned.
+	dates := make([]string, 0, len(results))
+	for _, result := range results {
+		dates = append(dates, result.Date.Format("2006-01-02"))
+		if result.Date.Day() == 27 {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, "281.8289", result.Rates[sbpfx.USD].Ready)
+			continue
+		}
+		assert.IsError(t, result.Err, sbpfx.ErrSheetNotFound)
+		assert.Zero(t, result.Rates)
+	}
+	assert.Equal(t, []string{"2025-08-25", "2025-08-26", "2025-08-27", "2025-08-28", "2025-08-29"}, dates)
+	assert.Equal(t, 5, len(fixtures.Requests()))
+
+	_, err = client.GetExchangeRatesRange(t.Context(), to, from)
+	assert.Error(t, err)
+
+	_, err = client.GetExchangeRatesRange(t.Context(), from, to, sbpfx.Workers(0))
+	assert.Error(t, err)
+}
+
+func TestRates(t *testing.T) {
+	client, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+
+	from := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	var dates []string
+	var missing int
+	for rate, err := range client.Rates(t.Context(), from, to, sbpfx.USD) {
+		if errors.Is(err, sbpfx.ErrSheetNotFound) {
+			missing++
+			continue
+		}
+		assert.NoError(t, err)
+		dates = append(dates, rate.Date.Format("2006-01-02"))
+		assert.Equal(t, "281.8289", rate.Ready)
+	}
+	assert.Equal(t, []string{"2025-08-27"}, dates)
+	assert.Equal(t, 3, missing) // 28th, 29th and 1st; the weekend is skipped
+	assert.Equal(t, 4, len(fixtures.Requests()))
+
+	// Breaking out of the loop stops fetching.
+	for _, err := range client.Rates(t.Context(), from, to, sbpfx.USD) {
+		assert.NoError(t, err)
+		break
+	}
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	// Invalid options end the iteration with a single error.
+	var errs int
+	for _, err := range client.Rates(t.Context(), from, to, sbpfx.USD, sbpfx.LatestAvailable(-1)) {
+		assert.Error(t, err)
+		errs++
+	}
+	assert.Equal(t, 1, errs)
+}
@@ -0,0 +1,276 @@
+// Package server serves SBP exchange rates over HTTP as JSON, so services not
+// written in Go can use them:
+//
+//	GET /v1/rates/{date}             The sheet for a date (sbpfx.RateSheet)
+//	GET /v1/rates/{date}/{currency}  One currency's row (sbpfx.ExchangeRate)
+//	GET /v1/latest                   The most recent sheet, looking back ?lookback=N days (default 7)
+//	GET /v1/convert                  ?amount=&from=&to=, optionally &date=&precision=&rounding= (sbpfx.Conversion)
+//	GET /v1/sheets/{date}.pdf        The original PDF
+//
+// Dates are YYYY-MM-DD. Errors are JSON objects with an "error" field and
+// status 400 for a bad request, 404 if there is no sheet or the currency isn't
+// on it, 422 if the sheet couldn't be parsed and 502 if SBP couldn't be
+// reached.
+//
+// Sheets for earlier dates never change, so their responses are marked
+// immutable. Responses for today's sheet may be cached for five minutes.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mistermoe/sbpfx"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	defaultLookback  = 7
+	maxLookback      = 31
+	defaultPrecision = 4
+
+	cacheImmutable = "public, max-age=31536000, immutable"
+	cacheToday     = "public, max-age=300"
+	cacheNone      = "no-store"
+)
+
+// errBadRequest marks errors caused by a bad request.
+var errBadRequest = errors.New("bad request")
+
+// Server is an http.Handler serving a Client's rates.
+type Server struct {
+	client *sbpfx.Client
+	mux    *http.ServeMux
+}
+
+// New returns a Server backed by client. Give the client a sheet cache (and a
+// Cache or Store) so that repeated requests don't each go to SBP.
+func New(client *sbpfx.Client) *Server {
+	s := &Server{client: client, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/rates/{date}", s.handleSheet)
+	s.mux.HandleFunc("GET /v1/rates/{date}/{currency}", s.handleRate)
+	s.mux.HandleFunc("GET /v1/latest", s.handleLatest)
+	s.mux.HandleFunc("GET /v1/convert", s.handleConvert)
+	s.mux.HandleFunc("GET /v1/sheets/{file}", s.handlePDF)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleSheet(w http.ResponseWriter, r *http.Request) {
+	date, err := dateOption(r.PathValue("date"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	sheet, err := s.client.GetRateSheet(r.Context(), date)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, sheet.Date, sheet)
+}
+
+func (s *Server) handleRate(w http.ResponseWriter, r *http.Request) {
+	date, err := dateOption(r.PathValue("date"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	currency, err := parseCurrency(r.PathValue("currency"), false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rate, err := s.client.GetExchangeRate(r.Context(), currency, date)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, rate.Date, rate)
+}
+
+func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
+	lookback := defaultLookback
+	if value := r.URL.Query().Get("lookback"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > maxLookback {
+			writeError(w, fmt.Errorf("%w: lookback must be a number of days from 0 to %d", errBadRequest, maxLookback))
+			return
+		}
+		lookback = n
+	}
+
+	sheet, err := s.client.GetRateSheet(r.Context(), sbpfx.LatestAvailable(lookback))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The latest sheet changes once a new one is published.
+	w.Header().Set("Cache-Control", cacheToday)
+	writeJSON(w, http.StatusOK, sheet)
+}
+
+func (s *Server) handleConvert(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	amount, err := sbpfx.ParseRate(query.Get("amount"))
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: amount: %w", errBadRequest, err))
+		return
+	}
+	from, err := parseCurrency(query.Get("from"), true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	to, err := parseCurrency(query.Get("to"), true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	opts := []sbpfx.Option{sbpfx.Precision(defaultPrecision)}
+	if value := query.Get("date"); value != "" {
+		date, err := dateOption(value)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		opts = append(opts, date)
+	}
+	if value := query.Get("precision"); value != "" {
+		places, err := strconv.Atoi(value)
+		if err != nil || places < 0 || places > sbpfx.MaxPrecision {
+			writeError(w, fmt.Errorf("%w: precision must be a number of places from 0 to %d", errBadRequest, sbpfx.MaxPrecision))
+			return
+		}
+		opts = append(opts, sbpfx.Precision(places))
+	}
+	if value := query.Get("rounding"); value != "" {
+		mode, err := sbpfx.ParseRoundingMode(value)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+			return
+		}
+		opts = append(opts, sbpfx.Rounding(mode))
+	}
+
+	conversion, err := s.client.Convert(r.Context(), amount, from, to, opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, conversion.Date, conversion)
+}
+
+func (s *Server) handlePDF(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("file"), ".pdf")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	date, err := dateOption(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	entry, err := s.client.FetchRateSheet(r.Context(), date)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Location", entry.URL)
+	w.Header().Set("Cache-Control", s.cacheControl(entry.Date))
+	w.Header().Set("Etag", strconv.Quote(entry.SHA256))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.Content)
+}
+
+// cacheControl returns the Cache-Control header for a response about the
+// sheet for date.
+func (s *Server) cacheControl(date time.Time) string {
+	if date.Before(s.client.Today()) {
+		return cacheImmutable
+	}
+	return cacheToday
+}
+
+// writeJSON writes a successful response about the sheet for date.
+func (s *Server) writeJSON(w http.ResponseWriter, date time.Time, value any) {
+	w.Header().Set("Cache-Control", s.cacheControl(date))
+	writeJSON(w, http.StatusOK, value)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes err as a JSON error with the status it maps to. Errors
+// aren't cached, since a missing sheet may yet be published.
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Cache-Control", cacheNone)
+	writeJSON(w, statusCode(err), map[string]string{"error": err.Error()})
+}
+
+// statusCode maps an error to an HTTP status. As in the CLI, a network
+// failure wins over "not found", since a candidate that couldn't be reached
+// might have held the sheet.
+func statusCode(err error) int {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, sbpfx.ErrMalformedSheet):
+		return http.StatusUnprocessableEntity
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusBadGateway
+	case errors.Is(err, sbpfx.ErrSheetNotFound), errors.Is(err, sbpfx.ErrCurrencyNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// dateOption validates a YYYY-MM-DD date from the request.
+func dateOption(value string) (sbpfx.Option, error) {
+	if _, err := time.Parse(dateLayout, value); err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q, expected format: YYYY-MM-DD", errBadRequest, value)
+	}
+	return sbpfx.ForDate(value), nil
+}
+
+// parseCurrency parses a currency code, accepting PKR only if pkr is set.
+func parseCurrency(code string, pkr bool) (sbpfx.Currency, error) {
+	currency, err := sbpfx.ParseCurrency(code)
+	if err == nil && currency == sbpfx.PKR && !pkr {
+		err = fmt.Errorf("no rates are quoted for %s", currency)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	return currency, nil
+}
@@ -0,0 +1,161 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/httpr"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/calendar"
+	"github.com/mistermoe/sbpfx/server"
+	"github.com/mistermoe/sbpfx/vcr"
+)
+
+// newServer returns a Server whose client is served by a vcr.Server replaying
+// the named cassettes, and the vcr.Server so tests can serve more.
+func newServer(t *testing.T, cassettes ...string) (*server.Server, *vcr.Server) {
+	t.Helper()
+
+	paths := make([]string, 0, len(cassettes))
+	for _, name := range cassettes {
+		paths = append(paths, filepath.Join("..", "fixtures", name))
+	}
+	fixtures := vcr.NewServer(t, paths...)
+
+	client := sbpfx.New(httpr.HTTPClient(http.Client{Transport: fixtures.Transport()}), sbpfx.WithSheetCache(8))
+	return server.New(client), fixtures
+}
+
+func get(t *testing.T, handler http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, target, nil))
+	return rec
+}
+
+func TestRates(t *testing.T) {
+	srv, _ := newServer(t, "TestGetExchangeRates")
+
+	rec := get(t, srv, "/v1/rates/2025-08-27")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+
+	var sheet sbpfx.RateSheet
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sheet))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", sheet.URL)
+	assert.True(t, len(sheet.Rates) > 2)
+
+	rec = get(t, srv, "/v1/rates/2025-08-27/usd")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rate sbpfx.ExchangeRate
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rate))
+	assert.Equal(t, sbpfx.USD, rate.Currency)
+	assert.Equal(t, "281.8289", rate.Ready)
+}
+
+func TestLatest(t *testing.T) {
+	srv, fixtures := newServer(t, "TestGetExchangeRates")
+
+	// Serve the recorded 2025-08-27 sheet as the most recent business day's.
+	latest := sbpfx.New().Today()
+	if cal := calendar.Pakistan(); !cal.IsBusinessDay(latest) {
+		latest = cal.PreviousBusinessDay(latest)
+	}
+	latestURL := sbpfx.New().GetUrl(sbpfx.ForTime(latest))
+	u, err := url.Parse(latestURL)
+	assert.NoError(t, err)
+	recorded, ok := fixtures.Response("/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf")
+	assert.True(t, ok)
+	fixtures.Serve(u.Path, recorded)
+
+	rec := get(t, srv, "/v1/latest")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+
+	var sheet sbpfx.RateSheet
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sheet))
+	assert.Equal(t, latestURL, sheet.URL)
+	assert.Equal(t, latest, sheet.Date)
+}
+
+func TestConvert(t *testing.T) {
+	srv, _ := newServer(t, "TestGetExchangeRates")
+
+	rec := get(t, srv, "/v1/convert?amount=1000&from=EUR&to=USD&date=2025-08-27&precision=2")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var conversion sbpfx.Conversion
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &conversion))
+	assert.Equal(t, "1160.00", conversion.Result.String())
+	assert.Equal(t, "1.16", conversion.Rate.String())
+
+	rec = get(t, srv, "/v1/convert?amount=1000&from=EUR&to=USD&date=2025-08-27&precision=2&rounding=down")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &conversion))
+	assert.Equal(t, "1159.99", conversion.Result.String())
+}
+
+func TestSheetPDF(t *testing.T) {
+	srv, _ := newServer(t, "TestGetExchangeRates")
+
+	rec := get(t, srv, "/v1/sheets/2025-08-27.pdf")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/mark-to-market-revaluation-exchange-rate-27-Aug-25.pdf", rec.Header().Get("Content-Location"))
+	assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF"))
+
+	rec = get(t, srv, "/v1/sheets/2025-08-27.txt")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestErrors(t *testing.T) {
+	srv, _ := newServer(t, "TestGetExchangeRates", "TestGetExchangeRatesMalformedSheet")
+	unreachable := server.New(sbpfx.New(httpr.HTTPClient(http.Client{Transport: vcr.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	})})))
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		target  string
+		want    int
+	}{
+		{"bad date", srv, "/v1/rates/27-08-2025", http.StatusBadRequest},
+		{"unknown currency", srv, "/v1/rates/2025-08-27/XYZ", http.StatusBadRequest},
+		{"bad amount", srv, "/v1/convert?amount=1e3&from=EUR&to=USD", http.StatusBadRequest},
+		{"bad rounding", srv, "/v1/convert?amount=1&from=EUR&to=USD&rounding=nearest", http.StatusBadRequest},
+		{"bad lookback", srv, "/v1/latest?lookback=365", http.StatusBadRequest},
+		{"no sheet", srv, "/v1/rates/2025-08-28", http.StatusNotFound},
+		{"currency not on sheet", srv, "/v1/rates/2025-08-27/GNH", http.StatusNotFound},
+		{"no PDF", srv, "/v1/sheets/2025-08-28.pdf", http.StatusNotFound},
+		{"malformed sheet", srv, "/v1/rates/2026-06-01", http.StatusUnprocessableEntity},
+		{"network", unreachable, "/v1/rates/2025-08-27", http.StatusBadGateway},
+		{"unknown route", srv, "/v2/rates", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, tt.handler, tt.target)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+
+	rec := get(t, srv, "/v1/rates/2025-08-28")
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Contains(t, body.Error, "2025-08-28")
+}
@@ -0,0 +1,187 @@
+package sbpfx
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// todaySheetTTL bounds how long a parsed sheet for today is reused, since SBP
+// can re-post or correct the day's sheet. Earlier sheets never change.
+const todaySheetTTL = 5 * time.Minute
+
+// WithSheetCache keeps up to size parsed rate sheets in memory, keyed by
+// business date, so repeated lookups for a date (e.g. one per currency) skip
+// both the download and the parse. Today's sheet is reused for at most five
+// minutes. Disabled by default.
+func WithSheetCache(size int) ClientOption {
+	return func(c *Client) {
+		c.sheets = newSheetCache(size)
+	}
+}
+
+// sheetCache is a fixed-size LRU of parsed sheets. A nil *sheetCache is a valid
+// cache that never holds anything.
+type sheetCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type sheetCacheEntry struct {
+	key     string
+	sheet   *RateSheet
+	expires time.Time // zero if the sheet never expires
+}
+
+func newSheetCache(size int) *sheetCache {
+	if size < 1 {
+		return nil
+	}
+
+	return &sheetCache{
+		mu:      sync.Mutex{},
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (s *sheetCache) get(key string, now time.Time) (*RateSheet, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := elem.Value.(*sheetCacheEntry)
+	if !entry.expires.IsZero() && now.After(entry.expires) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	s.order.MoveToFront(elem)
+	return entry.sheet, true
+}
+
+func (s *sheetCache) put(key string, sheet *RateSheet, expires time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := &sheetCacheEntry{key: key, sheet: sheet, expires: expires}
+	if elem, ok := s.entries[key]; ok {
+		elem.Value = entry
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(entry)
+	if s.order.Len() > s.size {
+		oldest, _ := s.order.Remove(s.order.Back()).(*sheetCacheEntry)
+		delete(s.entries, oldest.key)
+	}
+}
+
+// flightGroup coalesces concurrent loads of the same sheet so that callers
+// asking for one date at the same time share a single download and parse.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	sheet   *RateSheet
+	err     error
+	waiters int                // callers still waiting on the load; guarded by flightGroup.mu
+	cancel  context.CancelFunc // cancels the load once no caller is waiting
+}
+
+// do runs load for key, unless a load for key is already running, in which
+// case it waits for that one and returns its result. The result is shared, so
+// callers must not modify the returned sheet.
+//
+// The load runs on its own goroutine under a context that keeps ctx's values
+// but not its cancellation, so one caller giving up doesn't fail the others. A
+// caller whose ctx is done stops waiting and gets ctx's error, and the load is
+// cancelled once every caller has stopped waiting.
+func (g *flightGroup) do(ctx context.Context, key string, load func(context.Context) (*RateSheet, error)) (*RateSheet, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+	f, ok := g.flights[key]
+	if !ok {
+		loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), sheet: nil, err: nil, waiters: 0, cancel: cancel}
+		g.flights[key] = f
+		go g.run(loadCtx, key, f, load)
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.sheet, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody wants the result; a later caller starts a fresh load.
+			f.cancel()
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// run loads key for f and hands the result to its waiters. A load that panics
+// fails its waiters rather than leaving them blocked.
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, load func(context.Context) (*RateSheet, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.sheet, f.err = nil, fmt.Errorf("rate sheet load for %s panicked: %v", key, r)
+		}
+
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+
+		f.cancel()
+		close(f.done)
+	}()
+
+	f.sheet, f.err = load(ctx)
+}
+
+// clone returns a deep copy of the sheet, so that shared and cached sheets are
+// never modified through what callers are handed.
+func (s *RateSheet) clone() *RateSheet {
+	sheet := *s
+	sheet.Rates = make([]*ExchangeRate, len(s.Rates))
+	for i, rate := range s.Rates {
+		copied := *rate
+		sheet.Rates[i] = &copied
+	}
+	sheet.Notes = slices.Clone(s.Notes)
+
+	return &sheet
+}
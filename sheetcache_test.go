@@ -0,0 +1,142 @@
+package sbpfx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// waitForWaiters blocks until n callers are waiting on the flight for key.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		g.mu.Lock()
+		f, ok := g.flights[key]
+		joined := ok && f.waiters == n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers never joined the flight for %s", n, key)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFlightGroupSharesLoad(t *testing.T) {
+	var g flightGroup
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (*RateSheet, error) {
+		loads.Add(1)
+		<-release
+		return &RateSheet{URL: "sheet"}, nil //nolint:exhaustruct
+	}
+
+	const callers = 6
+	sheets := make([]*RateSheet, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			sheets[i], errs[i] = g.do(t.Context(), "2025-08-27", load)
+		})
+	}
+
+	waitForWaiters(t, &g, "2025-08-27", callers)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for i := range callers {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "sheet", sheets[i].URL)
+	}
+}
+
+func TestFlightGroupCallerCancels(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	var loadErr error
+	load := func(ctx context.Context) (*RateSheet, error) {
+		<-release
+		loadErr = ctx.Err()
+		return &RateSheet{URL: "sheet"}, nil //nolint:exhaustruct
+	}
+
+	// The caller that started the load gives up; the other still gets the sheet.
+	leaderCtx, cancelLeader := context.WithCancel(t.Context())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := g.do(leaderCtx, "2025-08-27", load)
+		leader <- err
+	}()
+	waitForWaiters(t, &g, "2025-08-27", 1)
+
+	waiter := make(chan *RateSheet, 1)
+	go func() {
+		sheet, err := g.do(t.Context(), "2025-08-27", load)
+		assert.NoError(t, err)
+		waiter <- sheet
+	}()
+	waitForWaiters(t, &g, "2025-08-27", 2)
+
+	cancelLeader()
+	assert.IsError(t, <-leader, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "sheet", (<-waiter).URL)
+	assert.NoError(t, loadErr)
+}
+
+func TestFlightGroupAllCallersCancel(t *testing.T) {
+	var g flightGroup
+	cancelled := make(chan struct{})
+	load := func(ctx context.Context) (*RateSheet, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "2025-08-27", load)
+		done <- err
+	}()
+	waitForWaiters(t, &g, "2025-08-27", 1)
+
+	// Once nobody is waiting, the load is cancelled and a new caller starts afresh.
+	cancel()
+	assert.IsError(t, <-done, context.Canceled)
+	<-cancelled
+
+	sheet, err := g.do(t.Context(), "2025-08-27", func(context.Context) (*RateSheet, error) {
+		return &RateSheet{URL: "sheet"}, nil //nolint:exhaustruct
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sheet", sheet.URL)
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	var g flightGroup
+
+	_, err := g.do(t.Context(), "2025-08-27", func(context.Context) (*RateSheet, error) {
+		panic("boom")
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.False(t, errors.Is(err, context.Canceled))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	assert.Equal(t, 0, len(g.flights))
+}
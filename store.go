@@ -0,0 +1,30 @@
+package sbpfx
+
+import (
+	"context"
+	"time"
+)
+
+// Store persists parsed rate sheets, e.g. in a database that reporting queries
+// run against. Unlike a Cache, which holds the raw PDFs, a Store saves clients
+// from parsing a sheet again. Implementations must be safe for concurrent use;
+// see the store package for a SQLite one.
+type Store interface {
+	// LoadSheet returns the sheet for a business date. It reports false, with a
+	// nil error, if the store doesn't hold one.
+	LoadSheet(ctx context.Context, date time.Time) (*RateSheet, bool, error)
+	// SaveSheet stores a sheet, replacing any existing sheet for its date.
+	SaveSheet(ctx context.Context, sheet *RateSheet) error
+}
+
+// WithStore makes the client look up parsed sheets in store before going to
+// SBP, and save every sheet it parses. Earlier sheets are served straight from
+// the store unless they were parsed by a different ParserVersion. Today's sheet
+// can still be re-posted, so it is always fetched (through the Cache, if any)
+// and the stored copy replaced. Errors reading or writing the store are
+// logged, and the sheet is fetched from SBP as if the store were empty.
+func WithStore(store Store) ClientOption {
+	return func(c *Client) {
+		c.store = store
+	}
+}
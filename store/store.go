@@ -0,0 +1,302 @@
+// Package store persists parsed SBP rate sheets in SQLite so that historical
+// queries don't need the PDFs again. It uses a pure-Go SQLite driver, so it
+// needs no cgo.
+//
+//	db, err := store.Open("rates.db")
+//	if err != nil {
+//		return err
+//	}
+//	defer db.Close()
+//
+//	client := sbpfx.New(sbpfx.WithStore(db))
+//
+// Rates are stored as the decimal strings SBP printed, so nothing is lost to
+// floating point. Dates are stored as YYYY-MM-DD, taken from the calendar day
+// of the time.Time passed in, which for the business dates sbpfx uses is
+// midnight UTC.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mistermoe/sbpfx"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+const dateLayout = "2006-01-02"
+
+// schema creates the tables if they don't exist. Each tenor has its own
+// column so that history for one tenor is a plain column query.
+const schema = `
+CREATE TABLE IF NOT EXISTS sheets (
+	date           TEXT PRIMARY KEY,
+	printed_date   TEXT NOT NULL,
+	url            TEXT NOT NULL,
+	sha256         TEXT NOT NULL,
+	fetched_at     TEXT NOT NULL,
+	parser_version TEXT NOT NULL,
+	notes          TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS rates (
+	date        TEXT    NOT NULL REFERENCES sheets (date) ON DELETE CASCADE,
+	position    INTEGER NOT NULL,
+	currency    TEXT    NOT NULL,
+	ready       TEXT    NOT NULL,
+	one_week    TEXT    NOT NULL,
+	two_week    TEXT    NOT NULL,
+	one_month   TEXT    NOT NULL,
+	two_month   TEXT    NOT NULL,
+	three_month TEXT    NOT NULL,
+	four_month  TEXT    NOT NULL,
+	five_month  TEXT    NOT NULL,
+	six_month   TEXT    NOT NULL,
+	nine_month  TEXT    NOT NULL,
+	one_year    TEXT    NOT NULL,
+	PRIMARY KEY (date, currency)
+);
+
+CREATE INDEX IF NOT EXISTS rates_currency_date ON rates (currency, date);
+`
+
+// rateColumns are the rates columns read back into an ExchangeRate, with the
+// sheet's URL joined in.
+const rateColumns = `r.currency, r.date, s.url, r.ready, r.one_week, r.two_week, r.one_month,
+	r.two_month, r.three_month, r.four_month, r.five_month, r.six_month, r.nine_month, r.one_year`
+
+// SQLite is an sbpfx.Store backed by a SQLite database. It is safe for
+// concurrent use.
+type SQLite struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at path, creating it and its tables if
+// needed.
+func Open(path string) (*SQLite, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create store schema in %s: %w", path, err)
+	}
+
+	return &SQLite{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// SaveSheet stores a sheet and its rows, replacing any existing sheet for its
+// date.
+func (s *SQLite) SaveSheet(ctx context.Context, sheet *sbpfx.RateSheet) error {
+	date := sheet.Date.Format(dateLayout)
+
+	notes, err := json.Marshal(sheet.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to encode notes for %s: %w", date, err)
+	}
+	printed := ""
+	if !sheet.PrintedDate.IsZero() {
+		printed = sheet.PrintedDate.Format(dateLayout)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save sheet for %s: %w", date, err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op once committed
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO sheets (date, printed_date, url, sha256, fetched_at, parser_version, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (date) DO UPDATE SET
+			printed_date = excluded.printed_date,
+			url = excluded.url,
+			sha256 = excluded.sha256,
+			fetched_at = excluded.fetched_at,
+			parser_version = excluded.parser_version,
+			notes = excluded.notes`,
+		date, printed, sheet.URL, sheet.SHA256, sheet.FetchedAt.UTC().Format(time.RFC3339Nano), sheet.ParserVersion, string(notes),
+	); err != nil {
+		return fmt.Errorf("failed to save sheet for %s: %w", date, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM rates WHERE date = ?`, date); err != nil {
+		return fmt.Errorf("failed to save rates for %s: %w", date, err)
+	}
+
+	for i, rate := range sheet.Rates {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO rates (date, position, currency, ready, one_week, two_week, one_month, two_month,
+				three_month, four_month, five_month, six_month, nine_month, one_year)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			date, i, string(rate.Currency), rate.Ready, rate.OneWeek, rate.TwoWeek, rate.OneMonth, rate.TwoMonth,
+			rate.ThreeMonth, rate.FourMonth, rate.FiveMonth, rate.SixMonth, rate.NineMonth, rate.OneYear,
+		); err != nil {
+			return fmt.Errorf("failed to save %s rate for %s: %w", rate.Currency, date, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save sheet for %s: %w", date, err)
+	}
+
+	return nil
+}
+
+// LoadSheet returns the sheet for a business date. It reports false, with a
+// nil error, if the store doesn't hold one.
+func (s *SQLite) LoadSheet(ctx context.Context, date time.Time) (*sbpfx.RateSheet, bool, error) {
+	sheets, err := s.Sheets(ctx, date, date)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(sheets) == 0 {
+		return nil, false, nil
+	}
+
+	return sheets[0], true, nil
+}
+
+// Sheets returns every stored sheet dated from from to to, inclusive, in date
+// order, each with its rows in sheet order.
+func (s *SQLite) Sheets(ctx context.Context, from, to time.Time) ([]*sbpfx.RateSheet, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT date, printed_date, url, sha256, fetched_at, parser_version, notes
+		FROM sheets
+		WHERE date BETWEEN ? AND ?
+		ORDER BY date`,
+		from.Format(dateLayout), to.Format(dateLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sheets: %w", err)
+	}
+	defer rows.Close()
+
+	var sheets []*sbpfx.RateSheet
+	byDate := map[string]*sbpfx.RateSheet{}
+	for rows.Next() {
+		sheet, err := scanSheet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+		byDate[sheet.Date.Format(dateLayout)] = sheet
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query sheets: %w", err)
+	}
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+
+	rates, err := s.queryRates(ctx, `
+		SELECT `+rateColumns+`
+		FROM rates r JOIN sheets s ON s.date = r.date
+		WHERE r.date BETWEEN ? AND ?
+		ORDER BY r.date, r.position`,
+		from.Format(dateLayout), to.Format(dateLayout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, rate := range rates {
+		sheet := byDate[rate.Date.Format(dateLayout)]
+		sheet.Rates = append(sheet.Rates, rate)
+	}
+
+	return sheets, nil
+}
+
+// Rates returns a currency's stored rows dated from from to to, inclusive, in
+// date order.
+func (s *SQLite) Rates(ctx context.Context, currency sbpfx.Currency, from, to time.Time) ([]*sbpfx.ExchangeRate, error) {
+	return s.queryRates(ctx, `
+		SELECT `+rateColumns+`
+		FROM rates r JOIN sheets s ON s.date = r.date
+		WHERE r.currency = ? AND r.date BETWEEN ? AND ?
+		ORDER BY r.date`,
+		string(currency), from.Format(dateLayout), to.Format(dateLayout),
+	)
+}
+
+func (s *SQLite) queryRates(ctx context.Context, query string, args ...any) ([]*sbpfx.ExchangeRate, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []*sbpfx.ExchangeRate
+	for rows.Next() {
+		var rate sbpfx.ExchangeRate
+		var currency, date string
+		if err := rows.Scan(
+			&currency, &date, &rate.URL, &rate.Ready, &rate.OneWeek, &rate.TwoWeek, &rate.OneMonth, &rate.TwoMonth,
+			&rate.ThreeMonth, &rate.FourMonth, &rate.FiveMonth, &rate.SixMonth, &rate.NineMonth, &rate.OneYear,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read rate: %w", err)
+		}
+
+		rate.Currency = sbpfx.Currency(currency)
+		if rate.Date, err = time.Parse(dateLayout, date); err != nil {
+			return nil, fmt.Errorf("invalid date %q in store: %w", date, err)
+		}
+		rates = append(rates, &rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+func scanSheet(rows *sql.Rows) (*sbpfx.RateSheet, error) {
+	var date, printed, fetched, notes string
+	sheet := &sbpfx.RateSheet{
+		RequestedDate: time.Time{},
+		Date:          time.Time{},
+		PrintedDate:   time.Time{},
+		URL:           "",
+		SHA256:        "",
+		FetchedAt:     time.Time{},
+		ParserVersion: "",
+		Rates:         nil,
+		Notes:         nil,
+	}
+	if err := rows.Scan(&date, &printed, &sheet.URL, &sheet.SHA256, &fetched, &sheet.ParserVersion, &notes); err != nil {
+		return nil, fmt.Errorf("failed to read sheet: %w", err)
+	}
+
+	var err error
+	if sheet.Date, err = time.Parse(dateLayout, date); err != nil {
+		return nil, fmt.Errorf("invalid date %q in store: %w", date, err)
+	}
+	sheet.RequestedDate = sheet.Date
+	if printed != "" {
+		if sheet.PrintedDate, err = time.Parse(dateLayout, printed); err != nil {
+			return nil, fmt.Errorf("invalid printed date %q in store: %w", printed, err)
+		}
+	}
+	if sheet.FetchedAt, err = time.Parse(time.RFC3339Nano, fetched); err != nil {
+		return nil, fmt.Errorf("invalid fetch time %q in store: %w", fetched, err)
+	}
+	if err := json.Unmarshal([]byte(notes), &sheet.Notes); err != nil {
+		return nil, fmt.Errorf("invalid notes for %s in store: %w", date, err)
+	}
+
+	return sheet, nil
+}
+
+var _ sbpfx.Store = (*SQLite)(nil)
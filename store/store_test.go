@@ -0,0 +1,99 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/store"
+)
+
+func open(t *testing.T) *store.SQLite {
+	t.Helper()
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "rates.db"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func sheet(day int, usd string) *sbpfx.RateSheet {
+	date := time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC)
+	url := "https://www.sbp.org.pk/rates.pdf"
+	return &sbpfx.RateSheet{
+		RequestedDate: date,
+		Date:          date,
+		PrintedDate:   date,
+		URL:           url,
+		SHA256:        "abc123",
+		FetchedAt:     date.Add(10 * time.Hour),
+		ParserVersion: sbpfx.ParserVersion,
+		Rates: []*sbpfx.ExchangeRate{
+			row(sbpfx.USD, date, url, usd, "282.0792", "294.2690"),
+			row(sbpfx.BDT, date, url, "2.3153", "", ""),
+		},
+		Notes: []string{"Rates are indicative."},
+	}
+}
+
+func row(currency sbpfx.Currency, date time.Time, url, ready, oneWeek, oneYear string) *sbpfx.ExchangeRate {
+	rate := new(sbpfx.ExchangeRate)
+	rate.Currency, rate.Date, rate.URL = currency, date, url
+	rate.Ready, rate.OneWeek, rate.OneYear = ready, oneWeek, oneYear
+	return rate
+}
+
+func TestSaveAndLoadSheet(t *testing.T) {
+	db := open(t)
+
+	_, ok, err := db.LoadSheet(t.Context(), time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	want := sheet(27, "281.8289")
+	assert.NoError(t, db.SaveSheet(t.Context(), want))
+
+	got, ok, err := db.LoadSheet(t.Context(), want.Date)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+
+	// Saving again replaces the sheet and its rows.
+	replacement := sheet(27, "281.9000")
+	replacement.Rates = replacement.Rates[:1]
+	replacement.Notes = nil
+	replacement.PrintedDate = time.Time{}
+	assert.NoError(t, db.SaveSheet(t.Context(), replacement))
+
+	got, _, err = db.LoadSheet(t.Context(), want.Date)
+	assert.NoError(t, err)
+	assert.Equal(t, replacement, got)
+}
+
+func TestQueries(t *testing.T) {
+	db := open(t)
+
+	for day, usd := range map[int]string{25: "281.5000", 26: "281.7000", 27: "281.8289"} {
+		assert.NoError(t, db.SaveSheet(t.Context(), sheet(day, usd)))
+	}
+
+	from := time.Date(2025, 8, 26, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	rates, err := db.Rates(t.Context(), sbpfx.USD, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rates))
+	assert.Equal(t, []string{"281.7000", "281.8289"}, []string{rates[0].Ready, rates[1].Ready})
+	assert.Equal(t, from, rates[0].Date)
+
+	rates, err = db.Rates(t.Context(), sbpfx.EUR, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(rates))
+
+	sheets, err := db.Sheets(t.Context(), from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(sheets))
+	assert.Equal(t, sbpfx.BDT, sheets[1].Rates[1].Currency)
+}
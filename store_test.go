@@ -0,0 +1,70 @@
+package sbpfx_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+	"github.com/mistermoe/sbpfx/store"
+)
+
+func TestStore(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "rates.db"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+
+	// The first lookup parses the PDF and writes the sheet through.
+	first, err := fixtures.client(sbpfx.WithStore(db)).GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixtures.Requests()))
+
+	// A fresh client is served from the store without going to SBP.
+	second, err := fixtures.client(sbpfx.WithStore(db)).GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(fixtures.Requests()))
+	assert.Equal(t, first.SHA256, second.SHA256)
+	assert.Equal(t, first.URL, second.URL)
+	assert.Equal(t, first.Rates, second.Rates)
+	assert.True(t, first.FetchedAt.Equal(second.FetchedAt))
+
+	date := time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)
+	rates, err := db.Rates(t.Context(), sbpfx.USD, date.AddDate(0, 0, -7), date)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rates))
+	assert.Equal(t, "281.8289", rates[0].Ready)
+	assert.Equal(t, "294.2690", rates[0].OneYear)
+}
+
+// brokenStore is a Store whose every read and write fails.
+type brokenStore struct{}
+
+func (brokenStore) LoadSheet(context.Context, time.Time) (*sbpfx.RateSheet, bool, error) {
+	return nil, false, errors.New("database is locked")
+}
+
+func (brokenStore) SaveSheet(context.Context, *sbpfx.RateSheet) error {
+	return errors.New("database is locked")
+}
+
+func TestStoreFailure(t *testing.T) {
+	client, records := logRecords(t, "TestGetExchangeRates", sbpfx.WithStore(brokenStore{}))
+
+	// The sheet comes from SBP instead, and both failures are logged.
+	sheet, err := client.GetRateSheet(t.Context(), sbpfx.ForDate("2025-08-27"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-08-27", sheet.Date.Format("2006-01-02"))
+
+	logged := records()
+	for _, msg := range []string{"WARN failed to read stored rate sheet", "WARN failed to store rate sheet"} {
+		i := slices.Index(messages(logged), msg)
+		assert.NotEqual(t, -1, i, msg)
+		assert.Equal(t, "database is locked", logged[i]["error"])
+	}
+}
@@ -0,0 +1,55 @@
+package sbpfx
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the client's spans.
+const tracerName = "github.com/mistermoe/sbpfx"
+
+// Span attributes. URL and status code use the OpenTelemetry semantic
+// convention names; the rest are specific to sbpfx.
+const (
+	attrDate        = attribute.Key("sbpfx.date")        // Business date requested, YYYY-MM-DD
+	attrSheetDate   = attribute.Key("sbpfx.sheet_date")  // Business date of the sheet served
+	attrLookback    = attribute.Key("sbpfx.lookback")    // LatestAvailable lookback in days
+	attrCurrency    = attribute.Key("sbpfx.currency")    // Currency requested
+	attrCurrencies  = attribute.Key("sbpfx.currencies")  // Number of currency rows parsed
+	attrCacheHit    = attribute.Key("sbpfx.cache_hit")   // Whether the PDF cache held the sheet
+	attrScheme      = attribute.Key("sbpfx.scheme")      // Naming scheme of a candidate path
+	attrConditional = attribute.Key("sbpfx.conditional") // Whether a candidate request was conditional
+	attrOutcome     = attribute.Key("sbpfx.outcome")     // ok, not_modified, not_found, canceled or error
+	attrSoft404     = attribute.Key("sbpfx.soft_404")    // A 200 response that wasn't a PDF
+	attrBytes       = attribute.Key("sbpfx.bytes")       // Size of a response body or PDF
+	attrParser      = attribute.Key("sbpfx.parser")      // ParserVersion
+	attrURL         = attribute.Key("url.full")          // Candidate or resolved URL
+	attrStatusCode  = attribute.Key("http.response.status_code")
+	attrMethod      = attribute.Key("http.request.method")
+)
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider the client creates
+// spans with. Defaults to the global provider, which records nothing unless
+// one is installed with otel.SetTracerProvider.
+//
+// The client traces GetRateSheet, GetExchangeRates and GetExchangeRate;
+// fetchRateSheet, the resolution of a date's PDF (through the cache, if any);
+// fetchPDF, one span per candidate URL tried; probePDF, one span per candidate
+// probed under WithParallelProbe; and parsePDFContent.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+func defaultTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// recordError marks span as failed with err.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
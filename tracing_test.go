@@ -0,0 +1,89 @@
+package sbpfx_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/mistermoe/sbpfx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttrs returns a span's attributes keyed by name.
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRatesDualFormat")
+	client := fixtures.client(sbpfx.WithTracerProvider(tp))
+
+	rates, err := client.GetExchangeRates(t.Context(), sbpfx.ForDate("2026-07-17"))
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	// The long name is tried first and answers with an HTML page (a soft
+	// 404); the bare name serves the sheet.
+	assert.Equal(t, []string{"sbpfx.fetchPDF", "sbpfx.fetchPDF", "sbpfx.fetchRateSheet", "sbpfx.parsePDFContent", "sbpfx.GetExchangeRates"}, names)
+
+	long, bare, fetch, parse, root := spans[0], spans[1], spans[2], spans[3], spans[4]
+	assert.Equal(t, fetch.SpanContext().SpanID(), long.Parent().SpanID())
+	assert.Equal(t, fetch.SpanContext().SpanID(), bare.Parent().SpanID())
+	assert.Equal(t, root.SpanContext().SpanID(), fetch.Parent().SpanID())
+	assert.Equal(t, root.SpanContext().SpanID(), parse.Parent().SpanID())
+
+	attrs := spanAttrs(long)
+	assert.Equal(t, "long", attrs["sbpfx.scheme"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	assert.True(t, attrs["sbpfx.soft_404"].AsBool())
+	assert.Equal(t, "not_found", attrs["sbpfx.outcome"].AsString())
+
+	attrs = spanAttrs(bare)
+	assert.Equal(t, "https://www.sbp.org.pk/assets/document/17-Jul-26.pdf", attrs["url.full"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	assert.False(t, attrs["sbpfx.soft_404"].AsBool())
+	assert.True(t, attrs["sbpfx.bytes"].AsInt64() > 0)
+
+	assert.Equal(t, int64(len(rates)), spanAttrs(parse)["sbpfx.currencies"].AsInt64())
+
+	attrs = spanAttrs(root)
+	assert.Equal(t, "2026-07-17", attrs["sbpfx.date"].AsString())
+	assert.Equal(t, "2026-07-17", attrs["sbpfx.sheet_date"].AsString())
+	assert.Equal(t, int64(len(rates)), attrs["sbpfx.currencies"].AsInt64())
+	assert.Equal(t, codes.Unset, root.Status().Code)
+}
+
+func TestTracingError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, fixtures := newFixtureClient(t, "TestGetExchangeRates")
+	client := fixtures.client(sbpfx.WithTracerProvider(tp))
+
+	_, err := client.GetExchangeRate(t.Context(), sbpfx.USD, sbpfx.ForDate("2025-08-28"))
+	assert.IsError(t, err, sbpfx.ErrSheetNotFound)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 3, len(spans))
+	assert.Equal(t, int64(http.StatusNotFound), spanAttrs(spans[0])["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	root := spans[2]
+	assert.Equal(t, "sbpfx.GetExchangeRate", root.Name())
+	assert.Equal(t, "USD", spanAttrs(root)["sbpfx.currency"].AsString())
+	assert.Equal(t, codes.Error, root.Status().Code)
+}
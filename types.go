@@ -1,6 +1,10 @@
 package sbpfx
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Currency string
 
@@ -41,6 +45,10 @@ const (
 	GNH Currency = "GNH"
 )
 
+// PKR is the Pakistani Rupee. Every rate on the sheet is quoted in PKR, so it
+// never appears as a row, but it can be converted to and from.
+const PKR Currency = "PKR"
+
 func (c Currency) String() string {
 	return string(c)
 }
@@ -58,6 +66,46 @@ func (c Currency) IsValid() bool {
 	return validCurrencies[c]
 }
 
+// ParseCurrency returns the currency with the given code, ignoring case and
+// surrounding space. It accepts the currencies quoted on the sheet and PKR.
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !currency.IsValid() && currency != PKR {
+		return "", fmt.Errorf("unknown currency %q", code)
+	}
+	return currency, nil
+}
+
+// Tenor identifies a delivery-period column on the rate sheet. Its value is
+// the column header as SBP prints it.
+type Tenor string
+
+const (
+	TenorReady      Tenor = "READY"
+	TenorOneWeek    Tenor = "1-WEEK"
+	TenorTwoWeek    Tenor = "2-WEEK"
+	TenorOneMonth   Tenor = "1-MONTH"
+	TenorTwoMonth   Tenor = "2-MONTH"
+	TenorThreeMonth Tenor = "3-MONTH"
+	TenorFourMonth  Tenor = "4-MONTH"
+	TenorFiveMonth  Tenor = "5-MONTH"
+	TenorSixMonth   Tenor = "6-MONTH"
+	TenorNineMonth  Tenor = "9-MONTH"
+	TenorOneYear    Tenor = "1-YEAR"
+)
+
+// Tenors returns every tenor in the order the columns appear on the sheet.
+func Tenors() []Tenor {
+	return []Tenor{
+		TenorReady, TenorOneWeek, TenorTwoWeek, TenorOneMonth, TenorTwoMonth, TenorThreeMonth,
+		TenorFourMonth, TenorFiveMonth, TenorSixMonth, TenorNineMonth, TenorOneYear,
+	}
+}
+
+func (t Tenor) String() string {
+	return string(t)
+}
+
 // ExchangeRate represents exchange rates for different delivery periods
 // These are forward rates used for currency hedging and speculation.
 type ExchangeRate struct {
@@ -77,7 +125,127 @@ type ExchangeRate struct {
 	OneYear    string    `json:"one_year,omitempty"`    // 1-year forward rate
 }
 
+// ParserVersion identifies the rate-sheet parser that produced a RateSheet. It
+// changes whenever parsing changes in a way that could alter extracted values.
+const ParserVersion = "2"
+
+// RateSheet is one day's SBP rate sheet: every currency row in the order SBP
+// printed it, along with where and when the sheet was fetched.
+type RateSheet struct {
+	RequestedDate time.Time       `json:"requested_date"`        // Business date that was asked for
+	Date          time.Time       `json:"date"`                  // Business date of the sheet served (earlier after a LatestAvailable fallback)
+	PrintedDate   time.Time       `json:"printed_date,omitzero"` // Date printed on the sheet, if one was found
+	URL           string          `json:"url"`                   // Source PDF URL
+	SHA256        string          `json:"sha256"`                // Hex-encoded SHA-256 of the source PDF
+	FetchedAt     time.Time       `json:"fetched_at"`            // When the PDF was downloaded
+	ParserVersion string          `json:"parser_version"`        // ParserVersion that parsed the sheet
+	Rates         []*ExchangeRate `json:"rates"`                 // Currency rows in sheet order
+	Notes         []string        `json:"notes,omitempty"`       // Notes and footer text below the table
+}
+
+// Rate returns the row for a currency, if the sheet quotes it.
+func (s *RateSheet) Rate(currency Currency) (*ExchangeRate, bool) {
+	for _, rate := range s.Rates {
+		if rate.Currency == currency {
+			return rate, true
+		}
+	}
+	return nil, false
+}
+
+// Map returns the sheet's rows keyed by currency.
+func (s *RateSheet) Map() map[Currency]*ExchangeRate {
+	rates := make(map[Currency]*ExchangeRate, len(s.Rates))
+	for _, rate := range s.Rates {
+		rates[rate.Currency] = rate
+	}
+	return rates
+}
+
 // GetSpotRate returns the spot rate (Ready rate) as a string.
 func (e *ExchangeRate) GetSpotRate() string {
 	return e.Ready
 }
+
+// Tenor returns the rate quoted for the given tenor, or "" if the sheet did not
+// quote one.
+func (e *ExchangeRate) Tenor(t Tenor) string {
+	if field := e.tenorField(t); field != nil {
+		return *field
+	}
+	return ""
+}
+
+// TenorRate returns the rate quoted for the given tenor as an exact decimal. It
+// reports false if the sheet did not quote one.
+func (e *ExchangeRate) TenorRate(t Tenor) (Rate, bool) {
+	rate, err := ParseRate(e.Tenor(t))
+	if err != nil {
+		return Rate{}, false
+	}
+	return rate, true
+}
+
+// ReadyRate returns the spot rate as an exact decimal.
+func (e *ExchangeRate) ReadyRate() (Rate, bool) { return e.TenorRate(TenorReady) }
+
+// OneWeekRate returns the 1-week forward rate as an exact decimal.
+func (e *ExchangeRate) OneWeekRate() (Rate, bool) { return e.TenorRate(TenorOneWeek) }
+
+// TwoWeekRate returns the 2-week forward rate as an exact decimal.
+func (e *ExchangeRate) TwoWeekRate() (Rate, bool) { return e.TenorRate(TenorTwoWeek) }
+
+// OneMonthRate returns the 1-month forward rate as an exact decimal.
+func (e *ExchangeRate) OneMonthRate() (Rate, bool) { return e.TenorRate(TenorOneMonth) }
+
+// TwoMonthRate returns the 2-month forward rate as an exact decimal.
+func (e *ExchangeRate) TwoMonthRate() (Rate, bool) { return e.TenorRate(TenorTwoMonth) }
+
+// ThreeMonthRate returns the 3-month forward rate as an exact decimal.
+func (e *ExchangeRate) ThreeMonthRate() (Rate, bool) { return e.TenorRate(TenorThreeMonth) }
+
+// FourMonthRate returns the 4-month forward rate as an exact decimal.
+func (e *ExchangeRate) FourMonthRate() (Rate, bool) { return e.TenorRate(TenorFourMonth) }
+
+// FiveMonthRate returns the 5-month forward rate as an exact decimal.
+func (e *ExchangeRate) FiveMonthRate() (Rate, bool) { return e.TenorRate(TenorFiveMonth) }
+
+// SixMonthRate returns the 6-month forward rate as an exact decimal.
+func (e *ExchangeRate) SixMonthRate() (Rate, bool) { return e.TenorRate(TenorSixMonth) }
+
+// NineMonthRate returns the 9-month forward rate as an exact decimal.
+func (e *ExchangeRate) NineMonthRate() (Rate, bool) { return e.TenorRate(TenorNineMonth) }
+
+// OneYearRate returns the 1-year forward rate as an exact decimal.
+func (e *ExchangeRate) OneYearRate() (Rate, bool) { return e.TenorRate(TenorOneYear) }
+
+// tenorField returns a pointer to the field holding the given tenor's rate, or
+// nil for an unknown tenor.
+func (e *ExchangeRate) tenorField(t Tenor) *string {
+	switch t {
+	case TenorReady:
+		return &e.Ready
+	case TenorOneWeek:
+		return &e.OneWeek
+	case TenorTwoWeek:
+		return &e.TwoWeek
+	case TenorOneMonth:
+		return &e.OneMonth
+	case TenorTwoMonth:
+		return &e.TwoMonth
+	case TenorThreeMonth:
+		return &e.ThreeMonth
+	case TenorFourMonth:
+		return &e.FourMonth
+	case TenorFiveMonth:
+		return &e.FiveMonth
+	case TenorSixMonth:
+		return &e.SixMonth
+	case TenorNineMonth:
+		return &e.NineMonth
+	case TenorOneYear:
+		return &e.OneYear
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package vcr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	libcassette "gopkg.in/dnaeon/go-vcr.v3/cassette"
+)
+
+// Server replays the responses recorded in a set of cassettes by URL path,
+// whatever host a request names, and 404s every other path, so tests can walk
+// across requests that were never recorded. It records every path requested.
+// Conditional requests that match a response's validators get a 304 Not
+// Modified.
+type Server struct {
+	transport http.RoundTripper
+
+	mu          sync.Mutex
+	responses   map[string]libcassette.Response
+	requests    []string
+	notModified int
+}
+
+// NewServer starts a Server replaying the cassettes at the given paths, as
+// passed to cassette.Load (without the .yaml extension). It is stopped when
+// the test ends.
+func NewServer(t *testing.T, cassettes ...string) *Server {
+	t.Helper()
+
+	s := &Server{
+		transport:   nil,
+		mu:          sync.Mutex{},
+		responses:   map[string]libcassette.Response{},
+		requests:    nil,
+		notModified: 0,
+	}
+	for _, path := range cassettes {
+		c, err := libcassette.Load(path)
+		assert.NoError(t, err, "Failed to load cassette")
+
+		for _, interaction := range c.Interactions {
+			u, err := url.Parse(interaction.Request.URL)
+			assert.NoError(t, err, "Failed to parse cassette URL")
+			s.responses[u.Path] = interaction.Response
+		}
+	}
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	assert.NoError(t, err, "Failed to parse server URL")
+
+	// Point every request at the test server, whatever host it names.
+	s.transport = RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.URL.Scheme = target.Scheme
+		r.URL.Host = target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	return s
+}
+
+// Transport returns a RoundTripper that sends every request to the server.
+func (s *Server) Transport() http.RoundTripper {
+	return s.transport
+}
+
+// Response returns the response served for a URL path.
+func (s *Server) Response(path string) (libcassette.Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, ok := s.responses[path]
+	return resp, ok
+}
+
+// Serve serves resp for a URL path from now on.
+func (s *Server) Serve(path string, resp libcassette.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = resp
+}
+
+// Remove 404s a URL path from now on.
+func (s *Server) Remove(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.responses, path)
+}
+
+// Requests returns the paths requested so far and resets the record.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+// NotModified returns how many conditional requests got a 304 Not Modified.
+func (s *Server) NotModified() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.notModified
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	resp, ok := s.responses[r.URL.Path]
+	modified := !ok || !notModified(r, resp)
+	if !modified {
+		s.notModified++
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !modified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	for key, values := range resp.Headers {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.Code)
+	_, _ = w.Write([]byte(resp.Body))
+}
+
+// notModified reports whether a conditional request's validators match resp.
+func notModified(r *http.Request, resp libcassette.Response) bool {
+	if etag := r.Header.Get("If-None-Match"); etag != "" {
+		return etag == resp.Headers.Get("Etag")
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		return since == resp.Headers.Get("Last-Modified")
+	}
+	return false
+}
+
+// RoundTripperFunc adapts a function to an http.RoundTripper, e.g. to fail or
+// stall some requests and pass the rest to a Server's Transport.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f.
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}